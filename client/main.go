@@ -2,14 +2,23 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"hash/crc32"
 	"io"
 	"log"
+	"strings"
+	"sync"
 	"time"
 
 	userv1 "grpc-go-learning/gen/go/user/v1/user"
+	"grpc-go-learning/internal/requestid"
+	"grpc-go-learning/internal/tracing"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 
@@ -29,14 +38,18 @@ func testServerStreaming(client userv1.UserServiceClient)  {
     log.Fatalf("StreamNotifications failed: %v", err)
   }
 
+	// Publish notifications once the stream is open
+	totalNotifications := 5
+	go publishNotifications(client, "user_1", totalNotifications)
+
 	// Receive messages in a loop
-	for {
+	for received := 0; received < totalNotifications; received++ {
 		notification, err := stream.Recv()
 
 		// Check if stream is done
     if err == io.EOF {
-      log.Println("✅ Stream closed by server (all notifications received)")
-      break
+      log.Println("✅ Stream closed by server")
+      return
     }
 
 		// Check for other errors
@@ -51,9 +64,83 @@ func testServerStreaming(client userv1.UserServiceClient)  {
       notification.Message,
     )
 	}
+	log.Println("✅ All notifications received")
+}
+
+// publishNotifications publishes count notifications, retrying each one to show deduplication
+func publishNotifications(client userv1.UserServiceClient, userID string, count int)  {
+	// Give the server a moment to register the subscription
+	time.Sleep(time.Millisecond * 200)
+
+	// Keys are unique per run so a rerun isn't deduplicated against the last one
+	runID := time.Now().Unix()
+
+	for i := 1; i <= count; i++ {
+		notification := &userv1.Notification{
+			UserId:   userID,
+			Title:    fmt.Sprintf("Notification #%d", i),
+			Message:  fmt.Sprintf("This is notification number %d for user %s", i, userID),
+			Type:     userv1.NotificationType_NOTIFICATION_TYPE_INFO,
+			DedupKey: fmt.Sprintf("demo-%d-%d", runID, i),
+		}
+
+		// Publish twice, as a retrying producer would
+		for attempt := 1; attempt <= 2; attempt++ {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+			resp, err := client.PublishNotification(ctx, &userv1.PublishNotificationRequest{
+				Notification: notification,
+			})
+			cancel()
+
+			if err != nil {
+				log.Fatalf("PublishNotification failed: %v", err)
+			}
+			log.Printf("📣 Published %s (attempt %d): %s", resp.NotificationId, attempt, resp.Status)
+		}
+
+		time.Sleep(time.Millisecond * 500)
+	}
+}
+
+func testBroadcast(client userv1.UserServiceClient)  {
+	log.Println("\n========== Broadcast Notification ==========")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	// Publish to every user
+	resp, err := client.PublishNotification(ctx, &userv1.PublishNotificationRequest{
+		Notification: &userv1.Notification{
+			Title:   "Maintenance",
+			Message: "The service will be briefly unavailable tonight",
+			Type:    userv1.NotificationType_NOTIFICATION_TYPE_WARNING,
+		},
+		Audience: &userv1.NotificationAudience{
+			Target: &userv1.NotificationAudience_AllUsers{AllUsers: true},
+		},
+	})
+	if err != nil {
+		log.Fatalf("Broadcast failed: %v", err)
+	}
+	log.Printf("📣 Broadcast queued as %s", resp.JobId)
+
+	// Poll the job until delivery finishes
+	for {
+		job, err := client.GetNotificationJob(ctx, &userv1.GetNotificationJobRequest{JobId: resp.JobId})
+		if err != nil {
+			log.Fatalf("GetNotificationJob failed: %v", err)
+		}
+		log.Printf("📊 Job %s: %s, %d/%d delivered, %d undelivered, %d deduplicated",
+			job.JobId, job.State, job.Delivered, job.TotalRecipients, job.Undelivered, job.Deduplicated)
+
+		if job.State == userv1.NotificationJobState_NOTIFICATION_JOB_STATE_COMPLETED {
+			break
+		}
+		time.Sleep(time.Millisecond * 200)
+	}
 }
 
-func testClientStreaming(client userv1.UserServiceClient)  {
+func testClientStreaming(client userv1.UserServiceClient) string {
 	log.Println("\n========== Client-Side Streaming ==========")
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
@@ -66,13 +153,23 @@ func testClientStreaming(client userv1.UserServiceClient)  {
     log.Fatalf("UploadUserData failed: %v", err)
   }
 
+	// Create a 5KB JSON profile, padded with a long bio, to send in 5 chunks (1KB each)
+	chunkSize := 1000 // 1KB
+	totalChunks := 5
+	profile := `{"name":"Ruffy G","age":31,"bio":"%s"}`
+	padding := strings.Repeat("x", chunkSize*totalChunks-len(profile)+2)
+	payload := []byte(fmt.Sprintf(profile, padding))
+	digest := sha256.Sum256(payload)
+
 	// 1. Send metadata first
   err = stream.Send(&userv1.UploadUserDataRequest{
     Data: &userv1.UploadUserDataRequest_Metadata{
     	Metadata: &userv1.UserMetadata{
     	  UserId:    "user_1",
-    	  Filename:  "user_data.json",
-    	  TotalSize: 5000, // 5KB total
+    	  Filename:    "user_data.json",
+    	  TotalSize:   int64(len(payload)), // 5KB total
+    	  Sha256:      hex.EncodeToString(digest[:]),
+    	  ContentType: "application/json",
     	},
     },
   })
@@ -84,16 +181,9 @@ func testClientStreaming(client userv1.UserServiceClient)  {
 	log.Println("📤 Sent metadata")
 
 	// 2. Send data in 5 chunks (1KB each)
-	chunkSize := 1000 // 1KB
-  totalChunks := 5
-
 	for i := 0; i < totalChunks; i++ {
-		// Create fake data chunk
-    data := make([]byte, chunkSize)
-
-		for j := range data {
-      data[j] = byte(i) // Fill with chunk number
-    }
+		data := payload[i*chunkSize : (i+1)*chunkSize]
+		crc := crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli))
 
 		// Send chunk
     err = stream.Send(&userv1.UploadUserDataRequest{
@@ -101,6 +191,7 @@ func testClientStreaming(client userv1.UserServiceClient)  {
         Chunk: &userv1.UserDataChunk{
           Data:        data,
           ChunkNumber: int32(i),
+          Crc32C:      &crc,
         },
       },
     })
@@ -117,13 +208,499 @@ func testClientStreaming(client userv1.UserServiceClient)  {
   if err != nil {
     log.Fatalf("Failed to receive response: %v", err)
   }
-	log.Printf("✅ Upload complete! Upload ID: %s, Bytes received: %d, Success: %v", resp.UploadId, resp.BytesReceived, resp.Success)
+	log.Printf("✅ Upload complete! Upload ID: %s, Bytes received: %d, Success: %v, SHA-256: %s", resp.UploadId, resp.BytesReceived, resp.Success, resp.Sha256)
+	return resp.UploadId
+}
+
+func testResumableUpload(client userv1.UserServiceClient)  {
+	log.Println("\n========== Resumable Upload ==========")
+
+	chunkSize := 1000
+	totalChunks := 4
+	payload := make([]byte, chunkSize*totalChunks)
+	for j := range payload {
+		payload[j] = byte(j / chunkSize)
+	}
+
+	// sendChunks sends payload from chunk number first, stopping before chunk number last
+	sendChunks := func(stream userv1.UserService_UploadUserDataClient, first, last int) {
+		for i := first; i < last; i++ {
+			data := payload[i*chunkSize : (i+1)*chunkSize]
+			crc := crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli))
+			err := stream.Send(&userv1.UploadUserDataRequest{
+				Data: &userv1.UploadUserDataRequest_Chunk{
+					Chunk: &userv1.UserDataChunk{Data: data, ChunkNumber: int32(i), Crc32C: &crc},
+				},
+			})
+			if err != nil {
+				log.Fatalf("Failed to send chunk %d: %v", i, err)
+			}
+			log.Printf("📤 Sent chunk #%d (%d bytes)", i, len(data))
+		}
+	}
+
+	// 1. Start an upload and drop the connection halfway through
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.UploadUserData(ctx)
+	if err != nil {
+		log.Fatalf("UploadUserData failed: %v", err)
+	}
+	err = stream.Send(&userv1.UploadUserDataRequest{
+		Data: &userv1.UploadUserDataRequest_Metadata{
+			Metadata: &userv1.UserMetadata{
+				UserId:    "user_1",
+				Filename:    "large_file.bin",
+				TotalSize:   int64(len(payload)),
+				ContentType: "application/octet-stream",
+			},
+		},
+	})
+	if err != nil {
+		log.Fatalf("Failed to send metadata: %v", err)
+	}
+
+	// The server announces the upload ID in the response headers
+	header, err := stream.Header()
+	if err != nil {
+		log.Fatalf("Failed to read upload id: %v", err)
+	}
+	ids := header.Get("x-upload-id")
+	if len(ids) == 0 {
+		log.Fatalf("Server did not send an upload id")
+	}
+	uploadID := ids[0]
+	log.Printf("📤 Started upload %s", uploadID)
+
+	sendChunks(stream, 0, totalChunks/2)
+	time.Sleep(time.Millisecond * 500)
+	cancel()
+	log.Println("💥 Simulated connection loss")
+	time.Sleep(time.Millisecond * 500)
+
+	// 2. Ask the server where to resume from
+	ctx2, cancel2 := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel2()
+
+	uploadStatus, err := client.GetUploadStatus(ctx2, &userv1.GetUploadStatusRequest{
+		UploadId: uploadID,
+		UserId:   "user_1",
+	})
+	if err != nil {
+		log.Fatalf("GetUploadStatus failed: %v", err)
+	}
+	log.Printf("📊 Upload %s: %s, %d/%d bytes committed in %d chunks",
+		uploadID, uploadStatus.State, uploadStatus.CommittedBytes, uploadStatus.TotalSize, uploadStatus.ChunkCount)
+
+	// 3. Resume from the committed chunk
+	stream, err = client.UploadUserData(ctx2)
+	if err != nil {
+		log.Fatalf("UploadUserData failed: %v", err)
+	}
+	err = stream.Send(&userv1.UploadUserDataRequest{
+		Data: &userv1.UploadUserDataRequest_Metadata{
+			Metadata: &userv1.UserMetadata{
+				UserId:         "user_1",
+				TotalSize:      int64(len(payload)),
+				ResumeUploadId: uploadID,
+			},
+		},
+	})
+	if err != nil {
+		log.Fatalf("Failed to send metadata: %v", err)
+	}
+	sendChunks(stream, int(uploadStatus.ChunkCount), totalChunks)
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		log.Fatalf("Failed to receive response: %v", err)
+	}
+	log.Printf("✅ Resumed upload complete! Upload ID: %s, Bytes received: %d", resp.UploadId, resp.BytesReceived)
+}
+
+func testProgressUpload(client userv1.UserServiceClient) {
+	log.Println("\n========== Upload With Progress ==========")
+
+	chunkSize := 1000
+	totalChunks := 20
+	payload := make([]byte, chunkSize*totalChunks)
+	for j := range payload {
+		payload[j] = byte(j % 251)
+	}
+	sum := sha256.Sum256(payload)
+
+	// upload sends chunks from where the server says to continue, stopping before chunk number stop,
+	// and returns the last acknowledgement
+	upload := func(metadata *userv1.UserMetadata, stop int) *userv1.UploadProgress {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+
+		stream, err := client.UploadUserDataWithProgress(ctx)
+		if err != nil {
+			log.Fatalf("UploadUserDataWithProgress failed: %v", err)
+		}
+		err = stream.Send(&userv1.UploadUserDataRequest{
+			Data: &userv1.UploadUserDataRequest_Metadata{Metadata: metadata},
+		})
+		if err != nil {
+			log.Fatalf("Failed to send metadata: %v", err)
+		}
+
+		// The first ack names the upload and the chunk to continue from
+		ack, err := stream.Recv()
+		if err != nil {
+			log.Fatalf("Failed to receive first ack: %v", err)
+		}
+		log.Printf("📤 Upload %s starts at byte %d (chunk %d)", ack.UploadId, ack.CommittedBytes, ack.ChunkCount)
+
+		// Send while acks are received below
+		go func() {
+			for i := int(ack.ChunkCount); i < stop; i++ {
+				data := payload[i*chunkSize : (i+1)*chunkSize]
+				crc := crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli))
+				err := stream.Send(&userv1.UploadUserDataRequest{
+					Data: &userv1.UploadUserDataRequest_Chunk{
+						Chunk: &userv1.UserDataChunk{Data: data, ChunkNumber: int32(i), Crc32C: &crc},
+					},
+				})
+				if err != nil {
+					return
+				}
+				time.Sleep(time.Millisecond * 50)
+			}
+			if stop == totalChunks {
+				stream.CloseSend()
+			}
+		}()
+
+		for ack.Result == nil && (stop == totalChunks || int(ack.ChunkCount) < stop) {
+			ack, err = stream.Recv()
+			if err != nil {
+				log.Fatalf("Upload failed: %v", err)
+			}
+			log.Printf("📊 Progress: %d/%d bytes committed", ack.CommittedBytes, ack.TotalSize)
+		}
+		return ack
+	}
+
+	// 1. Upload half and drop the connection once the server has acknowledged it
+	ack := upload(&userv1.UserMetadata{
+		UserId:      "user_1",
+		Filename:    "progress.bin",
+		TotalSize:   int64(len(payload)),
+		Sha256:      hex.EncodeToString(sum[:]),
+		ContentType: "application/octet-stream",
+	}, totalChunks/2)
+	log.Printf("💥 Simulated connection loss after %d acknowledged bytes", ack.CommittedBytes)
+	time.Sleep(time.Millisecond * 500)
+
+	// 2. Resume; no status lookup is needed, the first ack says where to continue
+	ack = upload(&userv1.UserMetadata{
+		UserId:         "user_1",
+		TotalSize:      int64(len(payload)),
+		ResumeUploadId: ack.UploadId,
+	}, totalChunks)
+	log.Printf("✅ Upload with progress complete! Upload ID: %s, Bytes received: %d, SHA-256: %s",
+		ack.Result.UploadId, ack.Result.BytesReceived, ack.Result.Sha256)
+}
+
+func testQuota(client userv1.UserServiceClient)  {
+	log.Println("\n========== Upload Quota ==========")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	quota, err := client.GetUserQuota(ctx, &userv1.GetUserQuotaRequest{UserId: "user_1"})
+	if err != nil {
+		log.Fatalf("GetUserQuota failed: %v", err)
+	}
+	log.Printf("📊 Quota for %s: %d/%d bytes used, %d in progress, max upload %d bytes",
+		quota.UserId, quota.UsedBytes, quota.QuotaBytes, quota.InProgressBytes, quota.MaxUploadSize)
+
+	// TRY TO UPLOAD MORE THAN THE MAXIMUM (ERROR HANDLING)
+	stream, err := client.UploadUserData(ctx)
+	if err != nil {
+		log.Fatalf("UploadUserData failed: %v", err)
+	}
+	err = stream.Send(&userv1.UploadUserDataRequest{
+		Data: &userv1.UploadUserDataRequest_Metadata{
+			Metadata: &userv1.UserMetadata{
+				UserId:    "user_1",
+				Filename:  "too_big.bin",
+				TotalSize: quota.MaxUploadSize + 1,
+			},
+		},
+	})
+	if err == nil {
+		_, err = stream.CloseAndRecv()
+	}
+	if err != nil {
+		log.Printf("❌ Expected error for oversized upload: %v", err)
+	}
+}
+
+func testListAndDeleteUploads(client userv1.UserServiceClient)  {
+	log.Println("\n========== List and Delete Uploads ==========")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	// Page through the uploads two at a time
+	var uploads []*userv1.UploadInfo
+	pageToken := ""
+	for {
+		resp, err := client.ListUploads(ctx, &userv1.ListUploadsRequest{
+			UserId:    "user_1",
+			PageSize:  2,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			log.Fatalf("ListUploads failed: %v", err)
+		}
+		for _, upload := range resp.Uploads {
+			log.Printf("📄 %s: %s (%d bytes, sha256 %.12s…)", upload.UploadId, upload.Filename, upload.Size, upload.Sha256)
+		}
+		uploads = append(uploads, resp.Uploads...)
+
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	if len(uploads) == 0 {
+		return
+	}
+
+	// Delete the oldest upload
+	resp, err := client.DeleteUpload(ctx, &userv1.DeleteUploadRequest{
+		UploadId: uploads[0].UploadId,
+		UserId:   "user_1",
+	})
+	if err != nil {
+		log.Fatalf("DeleteUpload failed: %v", err)
+	}
+	log.Printf("🗑️ Deleted %s, freed %d bytes", resp.UploadId, resp.BytesFreed)
+}
+
+func testMultipartUpload(client userv1.UserServiceClient)  {
+	log.Println("\n========== Multipart Upload ==========")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	partSize := 2000
+	totalParts := 3
+	payload := make([]byte, partSize*totalParts)
+	for j := range payload {
+		payload[j] = byte(j % 251)
+	}
+	digest := sha256.Sum256(payload)
+
+	// 1. Initiate the upload
+	initResp, err := client.InitiateUpload(ctx, &userv1.InitiateUploadRequest{
+		Metadata: &userv1.UserMetadata{
+			UserId:    "user_1",
+			Filename:    "multipart.bin",
+			TotalSize:   int64(len(payload)),
+			Sha256:      hex.EncodeToString(digest[:]),
+			ContentType: "application/octet-stream",
+		},
+	})
+	if err != nil {
+		log.Fatalf("InitiateUpload failed: %v", err)
+	}
+	log.Printf("📤 Initiated multipart upload %s", initResp.UploadId)
+
+	// 2. Send every part on its own stream, concurrently
+	parts := make([]*userv1.CompletedPart, totalParts)
+	var wg sync.WaitGroup
+	for i := 0; i < totalParts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			stream, err := client.UploadPart(ctx)
+			if err != nil {
+				log.Fatalf("UploadPart failed: %v", err)
+			}
+			err = stream.Send(&userv1.UploadPartRequest{
+				Data: &userv1.UploadPartRequest_Header{
+					Header: &userv1.UploadPartHeader{
+						UploadId:   initResp.UploadId,
+						UserId:     "user_1",
+						PartNumber: int32(i + 1),
+					},
+				},
+			})
+			if err != nil {
+				log.Fatalf("Failed to send part header: %v", err)
+			}
+
+			// Each part is sent as two chunks
+			part := payload[i*partSize : (i+1)*partSize]
+			for c, data := range [][]byte{part[:partSize/2], part[partSize/2:]} {
+				crc := crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli))
+				err = stream.Send(&userv1.UploadPartRequest{
+					Data: &userv1.UploadPartRequest_Chunk{
+						Chunk: &userv1.UserDataChunk{Data: data, ChunkNumber: int32(c), Crc32C: &crc},
+					},
+				})
+				if err != nil {
+					log.Fatalf("Failed to send chunk of part %d: %v", i+1, err)
+				}
+			}
+
+			resp, err := stream.CloseAndRecv()
+			if err != nil {
+				log.Fatalf("Failed to upload part %d: %v", i+1, err)
+			}
+			log.Printf("📤 Uploaded part %d (%d bytes)", resp.PartNumber, resp.Size)
+			parts[i] = &userv1.CompletedPart{PartNumber: resp.PartNumber, Sha256: resp.Sha256}
+		}(i)
+	}
+	wg.Wait()
+
+	// 3. Assemble the parts
+	resp, err := client.CompleteUpload(ctx, &userv1.CompleteUploadRequest{
+		UploadId: initResp.UploadId,
+		UserId:   "user_1",
+		Parts:    parts,
+	})
+	if err != nil {
+		log.Fatalf("CompleteUpload failed: %v", err)
+	}
+	log.Printf("✅ Multipart upload complete! Upload ID: %s, Bytes received: %d, SHA-256: %s", resp.UploadId, resp.BytesReceived, resp.Sha256)
+}
+
+func testDownload(client userv1.UserServiceClient, uploadID string)  {
+	log.Println("\n========== Download ==========")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
+	defer cancel()
+
+	// Download the second half of the upload in 1KB chunks
+	stream, err := client.DownloadUserData(ctx, &userv1.DownloadUserDataRequest{
+		UploadId:  uploadID,
+		UserId:    "user_1",
+		Offset:    2500,
+		ChunkSize: 1000,
+	})
+	if err != nil {
+		log.Fatalf("DownloadUserData failed: %v", err)
+	}
+
+	var bytesReceived int
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatalf("Error receiving chunk: %v", err)
+		}
+		bytesReceived += len(chunk.Data)
+		log.Printf("📥 Received chunk #%d (%d bytes)", chunk.ChunkNumber, len(chunk.Data))
+	}
+	log.Printf("✅ Download complete! Bytes received: %d", bytesReceived)
+
+	// TRY TO DOWNLOAD AS ANOTHER USER (ERROR HANDLING)
+	stream, err = client.DownloadUserData(ctx, &userv1.DownloadUserDataRequest{
+		UploadId: uploadID,
+		UserId:   "user_2",
+	})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if err != nil {
+		log.Printf("❌ Expected error for another user's upload: %v", err)
+	}
+}
+
+func testProcessing(client userv1.UserServiceClient, uploadID string) {
+	log.Println("\n========== Upload Processing ==========")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	// Processing runs in the background, poll until it settles
+	var processing *userv1.ProcessingStatus
+	for {
+		var err error
+		processing, err = client.GetProcessingStatus(ctx, &userv1.GetProcessingStatusRequest{
+			UploadId: uploadID,
+			UserId:   "user_1",
+		})
+		if err != nil {
+			log.Fatalf("GetProcessingStatus failed: %v", err)
+		}
+		if processing.State != userv1.ProcessingState_PROCESSING_STATE_PENDING &&
+			processing.State != userv1.ProcessingState_PROCESSING_STATE_RUNNING {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	log.Printf("📊 Processing of %s: %s", uploadID, processing.State)
+	for _, result := range processing.Results {
+		log.Printf("📄 %s: %s %s", result.Processor, result.State, result.Error)
+	}
+
+	// The profile importer merged user_data.json into the profile
+	user, err := client.GetUser(ctx, &userv1.GetUserRequest{UserId: "user_1"})
+	if err != nil {
+		log.Fatalf("GetUser failed: %v", err)
+	}
+	log.Printf("✅ Profile after import: %+v", user.User)
+}
+
+func testHealth(conn *grpc.ClientConn) {
+	log.Println("\n========== Health Check ==========")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	resp, err := healthgrpc.NewHealthClient(conn).Check(ctx, &healthgrpc.HealthCheckRequest{
+		Service: userv1.UserService_ServiceDesc.ServiceName,
+	})
+	if err != nil {
+		log.Fatalf("Health check failed: %v", err)
+	}
+	if resp.Status != healthgrpc.HealthCheckResponse_SERVING {
+		log.Fatalf("❌ UserService is %s", resp.Status)
+	}
+	log.Printf("✅ UserService is %s", resp.Status)
 }
 
 func main() {
+	traceExporter := flag.String("trace-exporter", "none", "where to export spans: none, stdout or file")
+	traceFile := flag.String("trace-file", "", "file spans are appended to with -trace-exporter=file")
+	addr := flag.String("addr", "localhost:50051", "server address")
+	var tlsOpts tlsOptions
+	flag.BoolVar(&tlsOpts.enabled, "tls", false, "connect with TLS, verifying the server against the system roots unless -ca-file is set")
+	flag.StringVar(&tlsOpts.caFile, "ca-file", "", "CA bundle the server certificate must chain to; implies -tls")
+	flag.StringVar(&tlsOpts.certFile, "cert-file", "", "client certificate for mutual TLS; implies -tls")
+	flag.StringVar(&tlsOpts.keyFile, "key-file", "", "client private key for mutual TLS; requires -cert-file")
+	flag.StringVar(&tlsOpts.serverName, "server-name", "", "name to verify the server certificate against instead of the host in -addr; implies -tls")
+	flag.Parse()
+
+	creds, err := tlsOpts.transportCredentials()
+	if err != nil {
+		log.Fatalf("Failed to set up TLS: %v", err)
+	}
+
+	// Every call carries a traceparent so its server spans join the client's trace
+	exporter, err := tracing.NewExporter(*traceExporter, *traceFile)
+	if err != nil {
+		log.Fatalf("Failed to create trace exporter: %v", err)
+	}
+	tracer := tracing.NewTracer("user-client", exporter)
+	defer tracer.Shutdown()
+
 	conn, err := grpc.NewClient(
-		"localhost:50051",
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		*addr,
+		grpc.WithTransportCredentials(creds),
+		// Each call also gets an x-request-id, printed on failure to find it in the server logs
+		grpc.WithChainUnaryInterceptor(tracer.UnaryClient, requestid.UnaryClient),
+		grpc.WithChainStreamInterceptor(tracer.StreamClient, requestid.StreamClient),
 	)
 
 	if err != nil {
@@ -136,6 +713,9 @@ func main() {
 
 	 log.Println("✅ Connected to gRPC server")
 
+	// Check the server is ready before using it
+	testHealth(conn)
+
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
@@ -183,6 +763,22 @@ func main() {
 
 	// Test server-side streaming
   testServerStreaming(client)
+	// Test audience publishing
+	testBroadcast(client)
 	// Test client-side streaming
-  testClientStreaming(client)
+  uploadID := testClientStreaming(client)
+	// Test server-side streaming download
+	testDownload(client, uploadID)
+	// Test post-upload processing
+	testProcessing(client, uploadID)
+	// Test resuming an interrupted upload
+	testResumableUpload(client)
+	// Test parallel multipart upload
+	testMultipartUpload(client)
+	// Test bidirectional upload with progress acks
+	testProgressUpload(client)
+	// Test upload limits
+	testQuota(client)
+	// Test listing and deleting uploads
+	testListAndDeleteUploads(client)
 }
\ No newline at end of file
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// tlsOptions configures how the client secures its connection
+type tlsOptions struct {
+	enabled    bool
+	caFile     string // CA bundle the server certificate must chain to; empty uses the system roots
+	certFile   string // client certificate, for servers that require mutual TLS
+	keyFile    string
+	serverName string // overrides the name checked against the server certificate
+}
+
+// wanted reports whether any TLS setting was given
+func (o tlsOptions) wanted() bool {
+	return o.enabled || o.caFile != "" || o.certFile != "" || o.keyFile != "" || o.serverName != ""
+}
+
+// transportCredentials returns TLS credentials, or plaintext ones when no TLS option is set
+func (o tlsOptions) transportCredentials() (credentials.TransportCredentials, error) {
+	if o.certFile != "" && o.keyFile == "" {
+		return nil, fmt.Errorf("-cert-file requires -key-file")
+	}
+	if o.keyFile != "" && o.certFile == "" {
+		return nil, fmt.Errorf("-key-file requires -cert-file")
+	}
+	if !o.wanted() {
+		return insecure.NewCredentials(), nil
+	}
+
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: o.serverName,
+	}
+
+	if o.caFile != "" {
+		pem, err := os.ReadFile(o.caFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA bundle: %w", err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", o.caFile)
+		}
+	}
+
+	if o.certFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.certFile, o.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(config), nil
+}
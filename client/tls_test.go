@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTransportCredentials(t *testing.T) {
+	tests := []struct {
+		name    string
+		options tlsOptions
+		wantErr string // empty: no error expected
+		wantTLS bool
+	}{
+		{name: "plaintext", options: tlsOptions{}},
+		{name: "tls", options: tlsOptions{enabled: true}, wantTLS: true},
+		{name: "server name implies tls", options: tlsOptions{serverName: "localhost"}, wantTLS: true},
+		{name: "key without certificate", options: tlsOptions{keyFile: "client-key.pem"}, wantErr: "-key-file requires -cert-file"},
+		{name: "certificate without key", options: tlsOptions{certFile: "client.pem"}, wantErr: "-cert-file requires -key-file"},
+		{name: "missing CA bundle", options: tlsOptions{caFile: "does-not-exist.pem"}, wantErr: "read CA bundle"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds, err := tt.options.transportCredentials()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("transportCredentials: %v", err)
+			}
+			if got := creds.Info().SecurityProtocol == "tls"; got != tt.wantTLS {
+				t.Errorf("security protocol %q, want tls %v", creds.Info().SecurityProtocol, tt.wantTLS)
+			}
+		})
+	}
+}
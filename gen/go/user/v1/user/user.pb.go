@@ -130,6 +130,218 @@ func (NotificationType) EnumDescriptor() ([]byte, []int) {
 	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{1}
 }
 
+// Outcome of a publish
+type PublishStatus int32
+
+const (
+	PublishStatus_PUBLISH_STATUS_UNSPECIFIED  PublishStatus = 0
+	PublishStatus_PUBLISH_STATUS_ACCEPTED     PublishStatus = 1
+	PublishStatus_PUBLISH_STATUS_DEDUPLICATED PublishStatus = 2
+	PublishStatus_PUBLISH_STATUS_QUEUED       PublishStatus = 3 // Audience publish accepted; track it with job_id
+)
+
+// Enum value maps for PublishStatus.
+var (
+	PublishStatus_name = map[int32]string{
+		0: "PUBLISH_STATUS_UNSPECIFIED",
+		1: "PUBLISH_STATUS_ACCEPTED",
+		2: "PUBLISH_STATUS_DEDUPLICATED",
+		3: "PUBLISH_STATUS_QUEUED",
+	}
+	PublishStatus_value = map[string]int32{
+		"PUBLISH_STATUS_UNSPECIFIED":  0,
+		"PUBLISH_STATUS_ACCEPTED":     1,
+		"PUBLISH_STATUS_DEDUPLICATED": 2,
+		"PUBLISH_STATUS_QUEUED":       3,
+	}
+)
+
+func (x PublishStatus) Enum() *PublishStatus {
+	p := new(PublishStatus)
+	*p = x
+	return p
+}
+
+func (x PublishStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PublishStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_user_v1_user_proto_enumTypes[2].Descriptor()
+}
+
+func (PublishStatus) Type() protoreflect.EnumType {
+	return &file_proto_user_v1_user_proto_enumTypes[2]
+}
+
+func (x PublishStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PublishStatus.Descriptor instead.
+func (PublishStatus) EnumDescriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{2}
+}
+
+// State of an audience publish
+type NotificationJobState int32
+
+const (
+	NotificationJobState_NOTIFICATION_JOB_STATE_UNSPECIFIED NotificationJobState = 0
+	NotificationJobState_NOTIFICATION_JOB_STATE_RUNNING     NotificationJobState = 1
+	NotificationJobState_NOTIFICATION_JOB_STATE_COMPLETED   NotificationJobState = 2
+)
+
+// Enum value maps for NotificationJobState.
+var (
+	NotificationJobState_name = map[int32]string{
+		0: "NOTIFICATION_JOB_STATE_UNSPECIFIED",
+		1: "NOTIFICATION_JOB_STATE_RUNNING",
+		2: "NOTIFICATION_JOB_STATE_COMPLETED",
+	}
+	NotificationJobState_value = map[string]int32{
+		"NOTIFICATION_JOB_STATE_UNSPECIFIED": 0,
+		"NOTIFICATION_JOB_STATE_RUNNING":     1,
+		"NOTIFICATION_JOB_STATE_COMPLETED":   2,
+	}
+)
+
+func (x NotificationJobState) Enum() *NotificationJobState {
+	p := new(NotificationJobState)
+	*p = x
+	return p
+}
+
+func (x NotificationJobState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NotificationJobState) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_user_v1_user_proto_enumTypes[3].Descriptor()
+}
+
+func (NotificationJobState) Type() protoreflect.EnumType {
+	return &file_proto_user_v1_user_proto_enumTypes[3]
+}
+
+func (x NotificationJobState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NotificationJobState.Descriptor instead.
+func (NotificationJobState) EnumDescriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{3}
+}
+
+// Upload lifecycle state
+type UploadState int32
+
+const (
+	UploadState_UPLOAD_STATE_UNSPECIFIED UploadState = 0
+	UploadState_UPLOAD_STATE_IN_PROGRESS UploadState = 1
+	UploadState_UPLOAD_STATE_COMPLETED   UploadState = 2
+)
+
+// Enum value maps for UploadState.
+var (
+	UploadState_name = map[int32]string{
+		0: "UPLOAD_STATE_UNSPECIFIED",
+		1: "UPLOAD_STATE_IN_PROGRESS",
+		2: "UPLOAD_STATE_COMPLETED",
+	}
+	UploadState_value = map[string]int32{
+		"UPLOAD_STATE_UNSPECIFIED": 0,
+		"UPLOAD_STATE_IN_PROGRESS": 1,
+		"UPLOAD_STATE_COMPLETED":   2,
+	}
+)
+
+func (x UploadState) Enum() *UploadState {
+	p := new(UploadState)
+	*p = x
+	return p
+}
+
+func (x UploadState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UploadState) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_user_v1_user_proto_enumTypes[4].Descriptor()
+}
+
+func (UploadState) Type() protoreflect.EnumType {
+	return &file_proto_user_v1_user_proto_enumTypes[4]
+}
+
+func (x UploadState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UploadState.Descriptor instead.
+func (UploadState) EnumDescriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{4}
+}
+
+// State of post-upload processing
+type ProcessingState int32
+
+const (
+	ProcessingState_PROCESSING_STATE_UNSPECIFIED ProcessingState = 0
+	ProcessingState_PROCESSING_STATE_PENDING     ProcessingState = 1
+	ProcessingState_PROCESSING_STATE_RUNNING     ProcessingState = 2
+	ProcessingState_PROCESSING_STATE_SUCCEEDED   ProcessingState = 3
+	ProcessingState_PROCESSING_STATE_FAILED      ProcessingState = 4
+	ProcessingState_PROCESSING_STATE_SKIPPED     ProcessingState = 5 // No processor applies to the upload
+)
+
+// Enum value maps for ProcessingState.
+var (
+	ProcessingState_name = map[int32]string{
+		0: "PROCESSING_STATE_UNSPECIFIED",
+		1: "PROCESSING_STATE_PENDING",
+		2: "PROCESSING_STATE_RUNNING",
+		3: "PROCESSING_STATE_SUCCEEDED",
+		4: "PROCESSING_STATE_FAILED",
+		5: "PROCESSING_STATE_SKIPPED",
+	}
+	ProcessingState_value = map[string]int32{
+		"PROCESSING_STATE_UNSPECIFIED": 0,
+		"PROCESSING_STATE_PENDING":     1,
+		"PROCESSING_STATE_RUNNING":     2,
+		"PROCESSING_STATE_SUCCEEDED":   3,
+		"PROCESSING_STATE_FAILED":      4,
+		"PROCESSING_STATE_SKIPPED":     5,
+	}
+)
+
+func (x ProcessingState) Enum() *ProcessingState {
+	p := new(ProcessingState)
+	*p = x
+	return p
+}
+
+func (x ProcessingState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProcessingState) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_user_v1_user_proto_enumTypes[5].Descriptor()
+}
+
+func (ProcessingState) Type() protoreflect.EnumType {
+	return &file_proto_user_v1_user_proto_enumTypes[5]
+}
+
+func (x ProcessingState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProcessingState.Descriptor instead.
+func (ProcessingState) EnumDescriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{5}
+}
+
 // Resquest message for GetUser
 type GetUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -456,7 +668,8 @@ type Notification struct {
 	Title          string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
 	Message        string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
 	Type           NotificationType       `protobuf:"varint,5,opt,name=type,proto3,enum=user.v1.NotificationType" json:"type,omitempty"`
-	Timestamp      int64                  `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Unix timestamp
+	Timestamp      int64                  `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`              // Unix timestamp
+	DedupKey       string                 `protobuf:"bytes,7,opt,name=dedup_key,json=dedupKey,proto3" json:"dedup_key,omitempty"` // Producer key; repeats for the same user are dropped within the dedup window
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -533,32 +746,36 @@ func (x *Notification) GetTimestamp() int64 {
 	return 0
 }
 
-// Request message (streamed multiple times by client)
-type UploadUserDataRequest struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Types that are valid to be assigned to Data:
-	//
-	//	*UploadUserDataRequest_Metadata
-	//	*UploadUserDataRequest_Chunk
-	Data          isUploadUserDataRequest_Data `protobuf_oneof:"data"`
+func (x *Notification) GetDedupKey() string {
+	if x != nil {
+		return x.DedupKey
+	}
+	return ""
+}
+
+// Request message for PublishNotification
+type PublishNotificationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Notification  *Notification          `protobuf:"bytes,1,opt,name=notification,proto3" json:"notification,omitempty"`
+	Audience      *NotificationAudience  `protobuf:"bytes,2,opt,name=audience,proto3" json:"audience,omitempty"` // When set, notification.user_id is ignored and delivery is asynchronous
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UploadUserDataRequest) Reset() {
-	*x = UploadUserDataRequest{}
+func (x *PublishNotificationRequest) Reset() {
+	*x = PublishNotificationRequest{}
 	mi := &file_proto_user_v1_user_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UploadUserDataRequest) String() string {
+func (x *PublishNotificationRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UploadUserDataRequest) ProtoMessage() {}
+func (*PublishNotificationRequest) ProtoMessage() {}
 
-func (x *UploadUserDataRequest) ProtoReflect() protoreflect.Message {
+func (x *PublishNotificationRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_user_v1_user_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -570,75 +787,51 @@ func (x *UploadUserDataRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UploadUserDataRequest.ProtoReflect.Descriptor instead.
-func (*UploadUserDataRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PublishNotificationRequest.ProtoReflect.Descriptor instead.
+func (*PublishNotificationRequest) Descriptor() ([]byte, []int) {
 	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *UploadUserDataRequest) GetData() isUploadUserDataRequest_Data {
-	if x != nil {
-		return x.Data
-	}
-	return nil
-}
-
-func (x *UploadUserDataRequest) GetMetadata() *UserMetadata {
+func (x *PublishNotificationRequest) GetNotification() *Notification {
 	if x != nil {
-		if x, ok := x.Data.(*UploadUserDataRequest_Metadata); ok {
-			return x.Metadata
-		}
+		return x.Notification
 	}
 	return nil
 }
 
-func (x *UploadUserDataRequest) GetChunk() *UserDataChunk {
+func (x *PublishNotificationRequest) GetAudience() *NotificationAudience {
 	if x != nil {
-		if x, ok := x.Data.(*UploadUserDataRequest_Chunk); ok {
-			return x.Chunk
-		}
+		return x.Audience
 	}
 	return nil
 }
 
-type isUploadUserDataRequest_Data interface {
-	isUploadUserDataRequest_Data()
-}
-
-type UploadUserDataRequest_Metadata struct {
-	Metadata *UserMetadata `protobuf:"bytes,1,opt,name=metadata,proto3,oneof"` // First message: metadata
-}
-
-type UploadUserDataRequest_Chunk struct {
-	Chunk *UserDataChunk `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"` // Subsequent messages: data chunks
-}
-
-func (*UploadUserDataRequest_Metadata) isUploadUserDataRequest_Data() {}
-
-func (*UploadUserDataRequest_Chunk) isUploadUserDataRequest_Data() {}
-
-type UserMetadata struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Filename      string                 `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
-	TotalSize     int64                  `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+// Audience of a multi-user publish
+type NotificationAudience struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Target:
+	//
+	//	*NotificationAudience_AllUsers
+	//	*NotificationAudience_Segment
+	Target        isNotificationAudience_Target `protobuf_oneof:"target"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UserMetadata) Reset() {
-	*x = UserMetadata{}
+func (x *NotificationAudience) Reset() {
+	*x = NotificationAudience{}
 	mi := &file_proto_user_v1_user_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserMetadata) String() string {
+func (x *NotificationAudience) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserMetadata) ProtoMessage() {}
+func (*NotificationAudience) ProtoMessage() {}
 
-func (x *UserMetadata) ProtoReflect() protoreflect.Message {
+func (x *NotificationAudience) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_user_v1_user_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -650,54 +843,76 @@ func (x *UserMetadata) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserMetadata.ProtoReflect.Descriptor instead.
-func (*UserMetadata) Descriptor() ([]byte, []int) {
+// Deprecated: Use NotificationAudience.ProtoReflect.Descriptor instead.
+func (*NotificationAudience) Descriptor() ([]byte, []int) {
 	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *UserMetadata) GetUserId() string {
+func (x *NotificationAudience) GetTarget() isNotificationAudience_Target {
 	if x != nil {
-		return x.UserId
+		return x.Target
 	}
-	return ""
+	return nil
 }
 
-func (x *UserMetadata) GetFilename() string {
+func (x *NotificationAudience) GetAllUsers() bool {
 	if x != nil {
-		return x.Filename
+		if x, ok := x.Target.(*NotificationAudience_AllUsers); ok {
+			return x.AllUsers
+		}
 	}
-	return ""
+	return false
 }
 
-func (x *UserMetadata) GetTotalSize() int64 {
+func (x *NotificationAudience) GetSegment() *UserSegment {
 	if x != nil {
-		return x.TotalSize
+		if x, ok := x.Target.(*NotificationAudience_Segment); ok {
+			return x.Segment
+		}
 	}
-	return 0
+	return nil
 }
 
-type UserDataChunk struct {
+type isNotificationAudience_Target interface {
+	isNotificationAudience_Target()
+}
+
+type NotificationAudience_AllUsers struct {
+	AllUsers bool `protobuf:"varint,1,opt,name=all_users,json=allUsers,proto3,oneof"`
+}
+
+type NotificationAudience_Segment struct {
+	Segment *UserSegment `protobuf:"bytes,2,opt,name=segment,proto3,oneof"`
+}
+
+func (*NotificationAudience_AllUsers) isNotificationAudience_Target() {}
+
+func (*NotificationAudience_Segment) isNotificationAudience_Target() {}
+
+// Users matching every set field
+type UserSegment struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
-	ChunkNumber   int32                  `protobuf:"varint,2,opt,name=chunk_number,json=chunkNumber,proto3" json:"chunk_number,omitempty"`
+	Status        UserStatus             `protobuf:"varint,1,opt,name=status,proto3,enum=user.v1.UserStatus" json:"status,omitempty"` // USER_STATUS_UNSPECIFIED matches any status
+	MinAge        int32                  `protobuf:"varint,2,opt,name=min_age,json=minAge,proto3" json:"min_age,omitempty"`
+	MaxAge        int32                  `protobuf:"varint,3,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"` // 0 means no upper bound
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UserDataChunk) Reset() {
-	*x = UserDataChunk{}
+func (x *UserSegment) Reset() {
+	*x = UserSegment{}
 	mi := &file_proto_user_v1_user_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserDataChunk) String() string {
+func (x *UserSegment) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserDataChunk) ProtoMessage() {}
+func (*UserSegment) ProtoMessage() {}
 
-func (x *UserDataChunk) ProtoReflect() protoreflect.Message {
+func (x *UserSegment) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_user_v1_user_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -709,49 +924,56 @@ func (x *UserDataChunk) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserDataChunk.ProtoReflect.Descriptor instead.
-func (*UserDataChunk) Descriptor() ([]byte, []int) {
+// Deprecated: Use UserSegment.ProtoReflect.Descriptor instead.
+func (*UserSegment) Descriptor() ([]byte, []int) {
 	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *UserDataChunk) GetData() []byte {
+func (x *UserSegment) GetStatus() UserStatus {
 	if x != nil {
-		return x.Data
+		return x.Status
 	}
-	return nil
+	return UserStatus_USER_STATUS_UNSPECIFIED
 }
 
-func (x *UserDataChunk) GetChunkNumber() int32 {
+func (x *UserSegment) GetMinAge() int32 {
 	if x != nil {
-		return x.ChunkNumber
+		return x.MinAge
 	}
 	return 0
 }
 
-// Response message (sent once by server)
-type UploadUserDataResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
-	BytesReceived int64                  `protobuf:"varint,2,opt,name=bytes_received,json=bytesReceived,proto3" json:"bytes_received,omitempty"`
-	Success       bool                   `protobuf:"varint,3,opt,name=success,proto3" json:"success,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *UserSegment) GetMaxAge() int32 {
+	if x != nil {
+		return x.MaxAge
+	}
+	return 0
 }
 
-func (x *UploadUserDataResponse) Reset() {
-	*x = UploadUserDataResponse{}
+// Response message for PublishNotification
+type PublishNotificationResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	NotificationId string                 `protobuf:"bytes,1,opt,name=notification_id,json=notificationId,proto3" json:"notification_id,omitempty"` // ID of the delivered notification (the original one when deduplicated)
+	Status         PublishStatus          `protobuf:"varint,2,opt,name=status,proto3,enum=user.v1.PublishStatus" json:"status,omitempty"`
+	JobId          string                 `protobuf:"bytes,3,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"` // Set for audience publishes
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PublishNotificationResponse) Reset() {
+	*x = PublishNotificationResponse{}
 	mi := &file_proto_user_v1_user_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UploadUserDataResponse) String() string {
+func (x *PublishNotificationResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UploadUserDataResponse) ProtoMessage() {}
+func (*PublishNotificationResponse) ProtoMessage() {}
 
-func (x *UploadUserDataResponse) ProtoReflect() protoreflect.Message {
+func (x *PublishNotificationResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_user_v1_user_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -763,46 +985,1825 @@ func (x *UploadUserDataResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UploadUserDataResponse.ProtoReflect.Descriptor instead.
-func (*UploadUserDataResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use PublishNotificationResponse.ProtoReflect.Descriptor instead.
+func (*PublishNotificationResponse) Descriptor() ([]byte, []int) {
 	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *UploadUserDataResponse) GetUploadId() string {
+func (x *PublishNotificationResponse) GetNotificationId() string {
 	if x != nil {
-		return x.UploadId
+		return x.NotificationId
 	}
 	return ""
 }
 
-func (x *UploadUserDataResponse) GetBytesReceived() int64 {
+func (x *PublishNotificationResponse) GetStatus() PublishStatus {
 	if x != nil {
-		return x.BytesReceived
+		return x.Status
 	}
-	return 0
+	return PublishStatus_PUBLISH_STATUS_UNSPECIFIED
 }
 
-func (x *UploadUserDataResponse) GetSuccess() bool {
+func (x *PublishNotificationResponse) GetJobId() string {
 	if x != nil {
-		return x.Success
+		return x.JobId
 	}
-	return false
+	return ""
 }
 
-var File_proto_user_v1_user_proto protoreflect.FileDescriptor
+// Request message for GetNotificationJob
+type GetNotificationJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_proto_user_v1_user_proto_rawDesc = "" +
-	"\n" +
-	"\x18proto/user/v1/user.proto\x12\auser.v1\")\n" +
-	"\x0eGetUserRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\"4\n" +
-	"\x0fGetUserResponse\x12!\n" +
-	"\x04user\x18\x01 \x01(\v2\r.user.v1.UserR\x04user\"O\n" +
-	"\x11CreateUSerRequest\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
-	"\x05email\x18\x02 \x01(\tR\x05email\x12\x10\n" +
-	"\x03age\x18\x03 \x01(\x05R\x03age\"7\n" +
-	"\x12CreateUserResponse\x12!\n" +
+func (x *GetNotificationJobRequest) Reset() {
+	*x = GetNotificationJobRequest{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNotificationJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNotificationJobRequest) ProtoMessage() {}
+
+func (x *GetNotificationJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNotificationJobRequest.ProtoReflect.Descriptor instead.
+func (*GetNotificationJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetNotificationJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+// Progress of an audience publish
+type NotificationJob struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	JobId           string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	State           NotificationJobState   `protobuf:"varint,2,opt,name=state,proto3,enum=user.v1.NotificationJobState" json:"state,omitempty"`
+	TotalRecipients int32                  `protobuf:"varint,3,opt,name=total_recipients,json=totalRecipients,proto3" json:"total_recipients,omitempty"`
+	Delivered       int32                  `protobuf:"varint,4,opt,name=delivered,proto3" json:"delivered,omitempty"`                        // Queued for at least one open subscription
+	Deduplicated    int32                  `protobuf:"varint,5,opt,name=deduplicated,proto3" json:"deduplicated,omitempty"`                  // Dropped as a repeat of an earlier dedup_key
+	CreatedAt       int64                  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`       // Unix timestamp
+	CompletedAt     int64                  `protobuf:"varint,7,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"` // Unix timestamp, 0 while running
+	Undelivered     int32                  `protobuf:"varint,8,opt,name=undelivered,proto3" json:"undelivered,omitempty"`                    // No open subscription, or every subscriber's buffer was full
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *NotificationJob) Reset() {
+	*x = NotificationJob{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationJob) ProtoMessage() {}
+
+func (x *NotificationJob) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationJob.ProtoReflect.Descriptor instead.
+func (*NotificationJob) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *NotificationJob) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *NotificationJob) GetState() NotificationJobState {
+	if x != nil {
+		return x.State
+	}
+	return NotificationJobState_NOTIFICATION_JOB_STATE_UNSPECIFIED
+}
+
+func (x *NotificationJob) GetTotalRecipients() int32 {
+	if x != nil {
+		return x.TotalRecipients
+	}
+	return 0
+}
+
+func (x *NotificationJob) GetDelivered() int32 {
+	if x != nil {
+		return x.Delivered
+	}
+	return 0
+}
+
+func (x *NotificationJob) GetDeduplicated() int32 {
+	if x != nil {
+		return x.Deduplicated
+	}
+	return 0
+}
+
+func (x *NotificationJob) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *NotificationJob) GetCompletedAt() int64 {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return 0
+}
+
+func (x *NotificationJob) GetUndelivered() int32 {
+	if x != nil {
+		return x.Undelivered
+	}
+	return 0
+}
+
+// Request message (streamed multiple times by client)
+type UploadUserDataRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Data:
+	//
+	//	*UploadUserDataRequest_Metadata
+	//	*UploadUserDataRequest_Chunk
+	Data          isUploadUserDataRequest_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadUserDataRequest) Reset() {
+	*x = UploadUserDataRequest{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadUserDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadUserDataRequest) ProtoMessage() {}
+
+func (x *UploadUserDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadUserDataRequest.ProtoReflect.Descriptor instead.
+func (*UploadUserDataRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UploadUserDataRequest) GetData() isUploadUserDataRequest_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *UploadUserDataRequest) GetMetadata() *UserMetadata {
+	if x != nil {
+		if x, ok := x.Data.(*UploadUserDataRequest_Metadata); ok {
+			return x.Metadata
+		}
+	}
+	return nil
+}
+
+func (x *UploadUserDataRequest) GetChunk() *UserDataChunk {
+	if x != nil {
+		if x, ok := x.Data.(*UploadUserDataRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isUploadUserDataRequest_Data interface {
+	isUploadUserDataRequest_Data()
+}
+
+type UploadUserDataRequest_Metadata struct {
+	Metadata *UserMetadata `protobuf:"bytes,1,opt,name=metadata,proto3,oneof"` // First message: metadata
+}
+
+type UploadUserDataRequest_Chunk struct {
+	Chunk *UserDataChunk `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"` // Subsequent messages: data chunks
+}
+
+func (*UploadUserDataRequest_Metadata) isUploadUserDataRequest_Data() {}
+
+func (*UploadUserDataRequest_Chunk) isUploadUserDataRequest_Data() {}
+
+type UserMetadata struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Filename       string                 `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
+	TotalSize      int64                  `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	Sha256         string                 `protobuf:"bytes,4,opt,name=sha256,proto3" json:"sha256,omitempty"`                                         // Expected hex-encoded SHA-256 of the whole upload, optional
+	ResumeUploadId string                 `protobuf:"bytes,5,opt,name=resume_upload_id,json=resumeUploadId,proto3" json:"resume_upload_id,omitempty"` // Continue an incomplete upload from its committed offset
+	ContentType    string                 `protobuf:"bytes,6,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`            // Declared media type, verified against the data; detected when empty
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UserMetadata) Reset() {
+	*x = UserMetadata{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserMetadata) ProtoMessage() {}
+
+func (x *UserMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserMetadata.ProtoReflect.Descriptor instead.
+func (*UserMetadata) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UserMetadata) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserMetadata) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *UserMetadata) GetTotalSize() int64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *UserMetadata) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *UserMetadata) GetResumeUploadId() string {
+	if x != nil {
+		return x.ResumeUploadId
+	}
+	return ""
+}
+
+func (x *UserMetadata) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+type UserDataChunk struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Data  []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	// 0 for the first chunk of a stream and one more for each after it, with no gaps
+	// or repeats; anything else is rejected with INVALID_ARGUMENT. A resumed upload
+	// continues from the chunk_count in GetUploadStatus, and every part of a multipart
+	// upload starts again at 0.
+	ChunkNumber   int32   `protobuf:"varint,2,opt,name=chunk_number,json=chunkNumber,proto3" json:"chunk_number,omitempty"`
+	Crc32C        *uint32 `protobuf:"varint,3,opt,name=crc32c,proto3,oneof" json:"crc32c,omitempty"` // CRC32C (Castagnoli) of data, verified when set
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserDataChunk) Reset() {
+	*x = UserDataChunk{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserDataChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserDataChunk) ProtoMessage() {}
+
+func (x *UserDataChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserDataChunk.ProtoReflect.Descriptor instead.
+func (*UserDataChunk) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UserDataChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *UserDataChunk) GetChunkNumber() int32 {
+	if x != nil {
+		return x.ChunkNumber
+	}
+	return 0
+}
+
+func (x *UserDataChunk) GetCrc32C() uint32 {
+	if x != nil && x.Crc32C != nil {
+		return *x.Crc32C
+	}
+	return 0
+}
+
+// Response message (sent once by server)
+type UploadUserDataResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	BytesReceived int64                  `protobuf:"varint,2,opt,name=bytes_received,json=bytesReceived,proto3" json:"bytes_received,omitempty"`
+	Success       bool                   `protobuf:"varint,3,opt,name=success,proto3" json:"success,omitempty"`
+	Sha256        string                 `protobuf:"bytes,4,opt,name=sha256,proto3" json:"sha256,omitempty"` // Hex-encoded SHA-256 computed by the server
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadUserDataResponse) Reset() {
+	*x = UploadUserDataResponse{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadUserDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadUserDataResponse) ProtoMessage() {}
+
+func (x *UploadUserDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadUserDataResponse.ProtoReflect.Descriptor instead.
+func (*UploadUserDataResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UploadUserDataResponse) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *UploadUserDataResponse) GetBytesReceived() int64 {
+	if x != nil {
+		return x.BytesReceived
+	}
+	return 0
+}
+
+func (x *UploadUserDataResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UploadUserDataResponse) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+// Acknowledgement sent during UploadUserDataWithProgress
+type UploadProgress struct {
+	state          protoimpl.MessageState  `protogen:"open.v1"`
+	UploadId       string                  `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	CommittedBytes int64                   `protobuf:"varint,2,opt,name=committed_bytes,json=committedBytes,proto3" json:"committed_bytes,omitempty"` // Bytes durably received; resume from here after a failure
+	ChunkCount     int32                   `protobuf:"varint,3,opt,name=chunk_count,json=chunkCount,proto3" json:"chunk_count,omitempty"`             // Chunks received, also the next chunk_number to send
+	TotalSize      int64                   `protobuf:"varint,4,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	Result         *UploadUserDataResponse `protobuf:"bytes,5,opt,name=result,proto3" json:"result,omitempty"` // Set on the last message once the upload is committed
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UploadProgress) Reset() {
+	*x = UploadProgress{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadProgress) ProtoMessage() {}
+
+func (x *UploadProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadProgress.ProtoReflect.Descriptor instead.
+func (*UploadProgress) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *UploadProgress) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *UploadProgress) GetCommittedBytes() int64 {
+	if x != nil {
+		return x.CommittedBytes
+	}
+	return 0
+}
+
+func (x *UploadProgress) GetChunkCount() int32 {
+	if x != nil {
+		return x.ChunkCount
+	}
+	return 0
+}
+
+func (x *UploadProgress) GetTotalSize() int64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *UploadProgress) GetResult() *UploadUserDataResponse {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+// Request message for DownloadUserData
+type DownloadUserDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`           // Requesting user; must own the upload
+	Offset        int64                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`                        // First byte to send
+	Length        int64                  `protobuf:"varint,4,opt,name=length,proto3" json:"length,omitempty"`                        // Number of bytes to send, 0 means to the end
+	ChunkSize     int32                  `protobuf:"varint,5,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"` // Bytes per chunk, 0 uses the server default
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadUserDataRequest) Reset() {
+	*x = DownloadUserDataRequest{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadUserDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadUserDataRequest) ProtoMessage() {}
+
+func (x *DownloadUserDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadUserDataRequest.ProtoReflect.Descriptor instead.
+func (*DownloadUserDataRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DownloadUserDataRequest) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *DownloadUserDataRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DownloadUserDataRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *DownloadUserDataRequest) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *DownloadUserDataRequest) GetChunkSize() int32 {
+	if x != nil {
+		return x.ChunkSize
+	}
+	return 0
+}
+
+// Request message for GetUploadStatus
+type GetUploadStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // Requesting user; must own the upload
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUploadStatusRequest) Reset() {
+	*x = GetUploadStatusRequest{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUploadStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUploadStatusRequest) ProtoMessage() {}
+
+func (x *GetUploadStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUploadStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetUploadStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetUploadStatusRequest) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *GetUploadStatusRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// Response message for GetUploadStatus
+type GetUploadStatusResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UploadId       string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	State          UploadState            `protobuf:"varint,2,opt,name=state,proto3,enum=user.v1.UploadState" json:"state,omitempty"`
+	CommittedBytes int64                  `protobuf:"varint,3,opt,name=committed_bytes,json=committedBytes,proto3" json:"committed_bytes,omitempty"` // Resume by sending data from this offset
+	ChunkCount     int32                  `protobuf:"varint,4,opt,name=chunk_count,json=chunkCount,proto3" json:"chunk_count,omitempty"`             // Resume with this chunk_number
+	TotalSize      int64                  `protobuf:"varint,5,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	ExpiresAt      int64                  `protobuf:"varint,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Unix timestamp when an idle incomplete upload is discarded, 0 otherwise
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetUploadStatusResponse) Reset() {
+	*x = GetUploadStatusResponse{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUploadStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUploadStatusResponse) ProtoMessage() {}
+
+func (x *GetUploadStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUploadStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetUploadStatusResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetUploadStatusResponse) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *GetUploadStatusResponse) GetState() UploadState {
+	if x != nil {
+		return x.State
+	}
+	return UploadState_UPLOAD_STATE_UNSPECIFIED
+}
+
+func (x *GetUploadStatusResponse) GetCommittedBytes() int64 {
+	if x != nil {
+		return x.CommittedBytes
+	}
+	return 0
+}
+
+func (x *GetUploadStatusResponse) GetChunkCount() int32 {
+	if x != nil {
+		return x.ChunkCount
+	}
+	return 0
+}
+
+func (x *GetUploadStatusResponse) GetTotalSize() int64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *GetUploadStatusResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+// Request message for GetUserQuota
+type GetUserQuotaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserQuotaRequest) Reset() {
+	*x = GetUserQuotaRequest{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserQuotaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserQuotaRequest) ProtoMessage() {}
+
+func (x *GetUserQuotaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserQuotaRequest.ProtoReflect.Descriptor instead.
+func (*GetUserQuotaRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetUserQuotaRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// Response message for GetUserQuota
+type GetUserQuotaResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	QuotaBytes      int64                  `protobuf:"varint,2,opt,name=quota_bytes,json=quotaBytes,proto3" json:"quota_bytes,omitempty"`                  // Storage allowed per user
+	UsedBytes       int64                  `protobuf:"varint,3,opt,name=used_bytes,json=usedBytes,proto3" json:"used_bytes,omitempty"`                     // Bytes in completed uploads
+	InProgressBytes int64                  `protobuf:"varint,4,opt,name=in_progress_bytes,json=inProgressBytes,proto3" json:"in_progress_bytes,omitempty"` // Bytes received by incomplete uploads
+	MaxUploadSize   int64                  `protobuf:"varint,5,opt,name=max_upload_size,json=maxUploadSize,proto3" json:"max_upload_size,omitempty"`       // Largest total_size accepted for a single upload
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetUserQuotaResponse) Reset() {
+	*x = GetUserQuotaResponse{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserQuotaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserQuotaResponse) ProtoMessage() {}
+
+func (x *GetUserQuotaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserQuotaResponse.ProtoReflect.Descriptor instead.
+func (*GetUserQuotaResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetUserQuotaResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetUserQuotaResponse) GetQuotaBytes() int64 {
+	if x != nil {
+		return x.QuotaBytes
+	}
+	return 0
+}
+
+func (x *GetUserQuotaResponse) GetUsedBytes() int64 {
+	if x != nil {
+		return x.UsedBytes
+	}
+	return 0
+}
+
+func (x *GetUserQuotaResponse) GetInProgressBytes() int64 {
+	if x != nil {
+		return x.InProgressBytes
+	}
+	return 0
+}
+
+func (x *GetUserQuotaResponse) GetMaxUploadSize() int64 {
+	if x != nil {
+		return x.MaxUploadSize
+	}
+	return 0
+}
+
+// Request message for ListUploads
+type ListUploadsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // 0 uses the server default
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token from the previous page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUploadsRequest) Reset() {
+	*x = ListUploadsRequest{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUploadsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUploadsRequest) ProtoMessage() {}
+
+func (x *ListUploadsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUploadsRequest.ProtoReflect.Descriptor instead.
+func (*ListUploadsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListUploadsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListUploadsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListUploadsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+// A completed upload
+type UploadInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Filename      string                 `protobuf:"bytes,3,opt,name=filename,proto3" json:"filename,omitempty"`
+	Size          int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	Sha256        string                 `protobuf:"bytes,5,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // Unix timestamp
+	ContentType   string                 `protobuf:"bytes,7,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadInfo) Reset() {
+	*x = UploadInfo{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadInfo) ProtoMessage() {}
+
+func (x *UploadInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadInfo.ProtoReflect.Descriptor instead.
+func (*UploadInfo) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *UploadInfo) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *UploadInfo) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UploadInfo) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *UploadInfo) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *UploadInfo) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *UploadInfo) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *UploadInfo) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+// Response message for ListUploads
+type ListUploadsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uploads       []*UploadInfo          `protobuf:"bytes,1,rep,name=uploads,proto3" json:"uploads,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUploadsResponse) Reset() {
+	*x = ListUploadsResponse{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUploadsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUploadsResponse) ProtoMessage() {}
+
+func (x *ListUploadsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUploadsResponse.ProtoReflect.Descriptor instead.
+func (*ListUploadsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListUploadsResponse) GetUploads() []*UploadInfo {
+	if x != nil {
+		return x.Uploads
+	}
+	return nil
+}
+
+func (x *ListUploadsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// Request message for DeleteUpload
+type DeleteUploadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // Requesting user; must own the upload
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUploadRequest) Reset() {
+	*x = DeleteUploadRequest{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUploadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUploadRequest) ProtoMessage() {}
+
+func (x *DeleteUploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUploadRequest.ProtoReflect.Descriptor instead.
+func (*DeleteUploadRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DeleteUploadRequest) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *DeleteUploadRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// Response message for DeleteUpload
+type DeleteUploadResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	BytesFreed    int64                  `protobuf:"varint,2,opt,name=bytes_freed,json=bytesFreed,proto3" json:"bytes_freed,omitempty"` // Bytes returned to the user's quota
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUploadResponse) Reset() {
+	*x = DeleteUploadResponse{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUploadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUploadResponse) ProtoMessage() {}
+
+func (x *DeleteUploadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUploadResponse.ProtoReflect.Descriptor instead.
+func (*DeleteUploadResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *DeleteUploadResponse) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *DeleteUploadResponse) GetBytesFreed() int64 {
+	if x != nil {
+		return x.BytesFreed
+	}
+	return 0
+}
+
+// Request message for InitiateUpload
+type InitiateUploadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metadata      *UserMetadata          `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"` // resume_upload_id must be empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InitiateUploadRequest) Reset() {
+	*x = InitiateUploadRequest{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InitiateUploadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InitiateUploadRequest) ProtoMessage() {}
+
+func (x *InitiateUploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InitiateUploadRequest.ProtoReflect.Descriptor instead.
+func (*InitiateUploadRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *InitiateUploadRequest) GetMetadata() *UserMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// Response message for InitiateUpload
+type InitiateUploadResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	MaxParts      int32                  `protobuf:"varint,2,opt,name=max_parts,json=maxParts,proto3" json:"max_parts,omitempty"` // Part numbers run from 1 to max_parts
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InitiateUploadResponse) Reset() {
+	*x = InitiateUploadResponse{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InitiateUploadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InitiateUploadResponse) ProtoMessage() {}
+
+func (x *InitiateUploadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InitiateUploadResponse.ProtoReflect.Descriptor instead.
+func (*InitiateUploadResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *InitiateUploadResponse) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *InitiateUploadResponse) GetMaxParts() int32 {
+	if x != nil {
+		return x.MaxParts
+	}
+	return 0
+}
+
+// Request message (streamed multiple times by client)
+type UploadPartRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Data:
+	//
+	//	*UploadPartRequest_Header
+	//	*UploadPartRequest_Chunk
+	Data          isUploadPartRequest_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadPartRequest) Reset() {
+	*x = UploadPartRequest{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadPartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadPartRequest) ProtoMessage() {}
+
+func (x *UploadPartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadPartRequest.ProtoReflect.Descriptor instead.
+func (*UploadPartRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *UploadPartRequest) GetData() isUploadPartRequest_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *UploadPartRequest) GetHeader() *UploadPartHeader {
+	if x != nil {
+		if x, ok := x.Data.(*UploadPartRequest_Header); ok {
+			return x.Header
+		}
+	}
+	return nil
+}
+
+func (x *UploadPartRequest) GetChunk() *UserDataChunk {
+	if x != nil {
+		if x, ok := x.Data.(*UploadPartRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isUploadPartRequest_Data interface {
+	isUploadPartRequest_Data()
+}
+
+type UploadPartRequest_Header struct {
+	Header *UploadPartHeader `protobuf:"bytes,1,opt,name=header,proto3,oneof"` // First message: which part this is
+}
+
+type UploadPartRequest_Chunk struct {
+	Chunk *UserDataChunk `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"` // Subsequent messages: data chunks
+}
+
+func (*UploadPartRequest_Header) isUploadPartRequest_Data() {}
+
+func (*UploadPartRequest_Chunk) isUploadPartRequest_Data() {}
+
+type UploadPartHeader struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PartNumber    int32                  `protobuf:"varint,3,opt,name=part_number,json=partNumber,proto3" json:"part_number,omitempty"` // Uploading a part number again replaces it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadPartHeader) Reset() {
+	*x = UploadPartHeader{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadPartHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadPartHeader) ProtoMessage() {}
+
+func (x *UploadPartHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadPartHeader.ProtoReflect.Descriptor instead.
+func (*UploadPartHeader) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *UploadPartHeader) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *UploadPartHeader) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UploadPartHeader) GetPartNumber() int32 {
+	if x != nil {
+		return x.PartNumber
+	}
+	return 0
+}
+
+// Response message for UploadPart
+type UploadPartResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	PartNumber    int32                  `protobuf:"varint,2,opt,name=part_number,json=partNumber,proto3" json:"part_number,omitempty"`
+	Size          int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Sha256        string                 `protobuf:"bytes,4,opt,name=sha256,proto3" json:"sha256,omitempty"` // Hex-encoded SHA-256 of the part, passed back in CompleteUpload
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadPartResponse) Reset() {
+	*x = UploadPartResponse{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadPartResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadPartResponse) ProtoMessage() {}
+
+func (x *UploadPartResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadPartResponse.ProtoReflect.Descriptor instead.
+func (*UploadPartResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *UploadPartResponse) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *UploadPartResponse) GetPartNumber() int32 {
+	if x != nil {
+		return x.PartNumber
+	}
+	return 0
+}
+
+func (x *UploadPartResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *UploadPartResponse) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+// Request message for CompleteUpload
+type CompleteUploadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Parts         []*CompletedPart       `protobuf:"bytes,3,rep,name=parts,proto3" json:"parts,omitempty"` // In ascending part_number order; unlisted parts are discarded
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteUploadRequest) Reset() {
+	*x = CompleteUploadRequest{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteUploadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteUploadRequest) ProtoMessage() {}
+
+func (x *CompleteUploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteUploadRequest.ProtoReflect.Descriptor instead.
+func (*CompleteUploadRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *CompleteUploadRequest) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *CompleteUploadRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CompleteUploadRequest) GetParts() []*CompletedPart {
+	if x != nil {
+		return x.Parts
+	}
+	return nil
+}
+
+type CompletedPart struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PartNumber    int32                  `protobuf:"varint,1,opt,name=part_number,json=partNumber,proto3" json:"part_number,omitempty"`
+	Sha256        string                 `protobuf:"bytes,2,opt,name=sha256,proto3" json:"sha256,omitempty"` // As returned by UploadPart
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompletedPart) Reset() {
+	*x = CompletedPart{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompletedPart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompletedPart) ProtoMessage() {}
+
+func (x *CompletedPart) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompletedPart.ProtoReflect.Descriptor instead.
+func (*CompletedPart) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *CompletedPart) GetPartNumber() int32 {
+	if x != nil {
+		return x.PartNumber
+	}
+	return 0
+}
+
+func (x *CompletedPart) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+// Request message for GetProcessingStatus
+type GetProcessingStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // Requesting user; must own the upload
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProcessingStatusRequest) Reset() {
+	*x = GetProcessingStatusRequest{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProcessingStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProcessingStatusRequest) ProtoMessage() {}
+
+func (x *GetProcessingStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProcessingStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetProcessingStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetProcessingStatusRequest) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *GetProcessingStatusRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// Outcome of one processor
+type ProcessorResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Processor     string                 `protobuf:"bytes,1,opt,name=processor,proto3" json:"processor,omitempty"`
+	State         ProcessingState        `protobuf:"varint,2,opt,name=state,proto3,enum=user.v1.ProcessingState" json:"state,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"` // Set when state is FAILED
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProcessorResult) Reset() {
+	*x = ProcessorResult{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessorResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessorResult) ProtoMessage() {}
+
+func (x *ProcessorResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessorResult.ProtoReflect.Descriptor instead.
+func (*ProcessorResult) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ProcessorResult) GetProcessor() string {
+	if x != nil {
+		return x.Processor
+	}
+	return ""
+}
+
+func (x *ProcessorResult) GetState() ProcessingState {
+	if x != nil {
+		return x.State
+	}
+	return ProcessingState_PROCESSING_STATE_UNSPECIFIED
+}
+
+func (x *ProcessorResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// Processing of one upload
+type ProcessingStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	State         ProcessingState        `protobuf:"varint,2,opt,name=state,proto3,enum=user.v1.ProcessingState" json:"state,omitempty"` // FAILED if any processor failed
+	Results       []*ProcessorResult     `protobuf:"bytes,3,rep,name=results,proto3" json:"results,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // Unix timestamp
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProcessingStatus) Reset() {
+	*x = ProcessingStatus{}
+	mi := &file_proto_user_v1_user_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessingStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessingStatus) ProtoMessage() {}
+
+func (x *ProcessingStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_v1_user_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessingStatus.ProtoReflect.Descriptor instead.
+func (*ProcessingStatus) Descriptor() ([]byte, []int) {
+	return file_proto_user_v1_user_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ProcessingStatus) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *ProcessingStatus) GetState() ProcessingState {
+	if x != nil {
+		return x.State
+	}
+	return ProcessingState_PROCESSING_STATE_UNSPECIFIED
+}
+
+func (x *ProcessingStatus) GetResults() []*ProcessorResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *ProcessingStatus) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+var File_proto_user_v1_user_proto protoreflect.FileDescriptor
+
+const file_proto_user_v1_user_proto_rawDesc = "" +
+	"\n" +
+	"\x18proto/user/v1/user.proto\x12\auser.v1\")\n" +
+	"\x0eGetUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"4\n" +
+	"\x0fGetUserResponse\x12!\n" +
+	"\x04user\x18\x01 \x01(\v2\r.user.v1.UserR\x04user\"O\n" +
+	"\x11CreateUSerRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x10\n" +
+	"\x03age\x18\x03 \x01(\x05R\x03age\"7\n" +
+	"\x12CreateUserResponse\x12!\n" +
 	"\x04user\x18\x01 \x01(\v2\r.user.v1.UserR\x04user\"\x88\x01\n" +
 	"\x04User\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
@@ -811,30 +2812,168 @@ const file_proto_user_v1_user_proto_rawDesc = "" +
 	"\x03age\x18\x04 \x01(\x05R\x03age\x12+\n" +
 	"\x06status\x18\x05 \x01(\x0e2\x13.user.v1.UserStatusR\x06status\"5\n" +
 	"\x1aStreamNotificationsRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xcd\x01\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xea\x01\n" +
 	"\fNotification\x12'\n" +
 	"\x0fnotification_id\x18\x01 \x01(\tR\x0enotificationId\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x14\n" +
 	"\x05title\x18\x03 \x01(\tR\x05title\x12\x18\n" +
 	"\amessage\x18\x04 \x01(\tR\amessage\x12-\n" +
 	"\x04type\x18\x05 \x01(\x0e2\x19.user.v1.NotificationTypeR\x04type\x12\x1c\n" +
-	"\ttimestamp\x18\x06 \x01(\x03R\ttimestamp\"\x84\x01\n" +
+	"\ttimestamp\x18\x06 \x01(\x03R\ttimestamp\x12\x1b\n" +
+	"\tdedup_key\x18\a \x01(\tR\bdedupKey\"\x92\x01\n" +
+	"\x1aPublishNotificationRequest\x129\n" +
+	"\fnotification\x18\x01 \x01(\v2\x15.user.v1.NotificationR\fnotification\x129\n" +
+	"\baudience\x18\x02 \x01(\v2\x1d.user.v1.NotificationAudienceR\baudience\"q\n" +
+	"\x14NotificationAudience\x12\x1d\n" +
+	"\tall_users\x18\x01 \x01(\bH\x00R\ballUsers\x120\n" +
+	"\asegment\x18\x02 \x01(\v2\x14.user.v1.UserSegmentH\x00R\asegmentB\b\n" +
+	"\x06target\"l\n" +
+	"\vUserSegment\x12+\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x13.user.v1.UserStatusR\x06status\x12\x17\n" +
+	"\amin_age\x18\x02 \x01(\x05R\x06minAge\x12\x17\n" +
+	"\amax_age\x18\x03 \x01(\x05R\x06maxAge\"\x8d\x01\n" +
+	"\x1bPublishNotificationResponse\x12'\n" +
+	"\x0fnotification_id\x18\x01 \x01(\tR\x0enotificationId\x12.\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x16.user.v1.PublishStatusR\x06status\x12\x15\n" +
+	"\x06job_id\x18\x03 \x01(\tR\x05jobId\"2\n" +
+	"\x19GetNotificationJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"\xae\x02\n" +
+	"\x0fNotificationJob\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x123\n" +
+	"\x05state\x18\x02 \x01(\x0e2\x1d.user.v1.NotificationJobStateR\x05state\x12)\n" +
+	"\x10total_recipients\x18\x03 \x01(\x05R\x0ftotalRecipients\x12\x1c\n" +
+	"\tdelivered\x18\x04 \x01(\x05R\tdelivered\x12\"\n" +
+	"\fdeduplicated\x18\x05 \x01(\x05R\fdeduplicated\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\x03R\tcreatedAt\x12!\n" +
+	"\fcompleted_at\x18\a \x01(\x03R\vcompletedAt\x12 \n" +
+	"\vundelivered\x18\b \x01(\x05R\vundelivered\"\x84\x01\n" +
 	"\x15UploadUserDataRequest\x123\n" +
 	"\bmetadata\x18\x01 \x01(\v2\x15.user.v1.UserMetadataH\x00R\bmetadata\x12.\n" +
 	"\x05chunk\x18\x02 \x01(\v2\x16.user.v1.UserDataChunkH\x00R\x05chunkB\x06\n" +
-	"\x04data\"b\n" +
+	"\x04data\"\xc7\x01\n" +
 	"\fUserMetadata\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
 	"\bfilename\x18\x02 \x01(\tR\bfilename\x12\x1d\n" +
 	"\n" +
-	"total_size\x18\x03 \x01(\x03R\ttotalSize\"F\n" +
+	"total_size\x18\x03 \x01(\x03R\ttotalSize\x12\x16\n" +
+	"\x06sha256\x18\x04 \x01(\tR\x06sha256\x12(\n" +
+	"\x10resume_upload_id\x18\x05 \x01(\tR\x0eresumeUploadId\x12!\n" +
+	"\fcontent_type\x18\x06 \x01(\tR\vcontentType\"n\n" +
 	"\rUserDataChunk\x12\x12\n" +
 	"\x04data\x18\x01 \x01(\fR\x04data\x12!\n" +
-	"\fchunk_number\x18\x02 \x01(\x05R\vchunkNumber\"v\n" +
+	"\fchunk_number\x18\x02 \x01(\x05R\vchunkNumber\x12\x1b\n" +
+	"\x06crc32c\x18\x03 \x01(\rH\x00R\x06crc32c\x88\x01\x01B\t\n" +
+	"\a_crc32c\"\x8e\x01\n" +
 	"\x16UploadUserDataResponse\x12\x1b\n" +
 	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12%\n" +
 	"\x0ebytes_received\x18\x02 \x01(\x03R\rbytesReceived\x12\x18\n" +
-	"\asuccess\x18\x03 \x01(\bR\asuccess*v\n" +
+	"\asuccess\x18\x03 \x01(\bR\asuccess\x12\x16\n" +
+	"\x06sha256\x18\x04 \x01(\tR\x06sha256\"\xcf\x01\n" +
+	"\x0eUploadProgress\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12'\n" +
+	"\x0fcommitted_bytes\x18\x02 \x01(\x03R\x0ecommittedBytes\x12\x1f\n" +
+	"\vchunk_count\x18\x03 \x01(\x05R\n" +
+	"chunkCount\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x04 \x01(\x03R\ttotalSize\x127\n" +
+	"\x06result\x18\x05 \x01(\v2\x1f.user.v1.UploadUserDataResponseR\x06result\"\x9e\x01\n" +
+	"\x17DownloadUserDataRequest\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x03R\x06offset\x12\x16\n" +
+	"\x06length\x18\x04 \x01(\x03R\x06length\x12\x1d\n" +
+	"\n" +
+	"chunk_size\x18\x05 \x01(\x05R\tchunkSize\"N\n" +
+	"\x16GetUploadStatusRequest\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\xea\x01\n" +
+	"\x17GetUploadStatusResponse\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12*\n" +
+	"\x05state\x18\x02 \x01(\x0e2\x14.user.v1.UploadStateR\x05state\x12'\n" +
+	"\x0fcommitted_bytes\x18\x03 \x01(\x03R\x0ecommittedBytes\x12\x1f\n" +
+	"\vchunk_count\x18\x04 \x01(\x05R\n" +
+	"chunkCount\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x05 \x01(\x03R\ttotalSize\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\x03R\texpiresAt\".\n" +
+	"\x13GetUserQuotaRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xc3\x01\n" +
+	"\x14GetUserQuotaResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1f\n" +
+	"\vquota_bytes\x18\x02 \x01(\x03R\n" +
+	"quotaBytes\x12\x1d\n" +
+	"\n" +
+	"used_bytes\x18\x03 \x01(\x03R\tusedBytes\x12*\n" +
+	"\x11in_progress_bytes\x18\x04 \x01(\x03R\x0finProgressBytes\x12&\n" +
+	"\x0fmax_upload_size\x18\x05 \x01(\x03R\rmaxUploadSize\"i\n" +
+	"\x12ListUploadsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"\xcc\x01\n" +
+	"\n" +
+	"UploadInfo\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1a\n" +
+	"\bfilename\x18\x03 \x01(\tR\bfilename\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x12\x16\n" +
+	"\x06sha256\x18\x05 \x01(\tR\x06sha256\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\x03R\tcreatedAt\x12!\n" +
+	"\fcontent_type\x18\a \x01(\tR\vcontentType\"l\n" +
+	"\x13ListUploadsResponse\x12-\n" +
+	"\auploads\x18\x01 \x03(\v2\x13.user.v1.UploadInfoR\auploads\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"K\n" +
+	"\x13DeleteUploadRequest\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"T\n" +
+	"\x14DeleteUploadResponse\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12\x1f\n" +
+	"\vbytes_freed\x18\x02 \x01(\x03R\n" +
+	"bytesFreed\"J\n" +
+	"\x15InitiateUploadRequest\x121\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x15.user.v1.UserMetadataR\bmetadata\"R\n" +
+	"\x16InitiateUploadResponse\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12\x1b\n" +
+	"\tmax_parts\x18\x02 \x01(\x05R\bmaxParts\"\x80\x01\n" +
+	"\x11UploadPartRequest\x123\n" +
+	"\x06header\x18\x01 \x01(\v2\x19.user.v1.UploadPartHeaderH\x00R\x06header\x12.\n" +
+	"\x05chunk\x18\x02 \x01(\v2\x16.user.v1.UserDataChunkH\x00R\x05chunkB\x06\n" +
+	"\x04data\"i\n" +
+	"\x10UploadPartHeader\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1f\n" +
+	"\vpart_number\x18\x03 \x01(\x05R\n" +
+	"partNumber\"~\n" +
+	"\x12UploadPartResponse\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12\x1f\n" +
+	"\vpart_number\x18\x02 \x01(\x05R\n" +
+	"partNumber\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\x12\x16\n" +
+	"\x06sha256\x18\x04 \x01(\tR\x06sha256\"{\n" +
+	"\x15CompleteUploadRequest\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12,\n" +
+	"\x05parts\x18\x03 \x03(\v2\x16.user.v1.CompletedPartR\x05parts\"H\n" +
+	"\rCompletedPart\x12\x1f\n" +
+	"\vpart_number\x18\x01 \x01(\x05R\n" +
+	"partNumber\x12\x16\n" +
+	"\x06sha256\x18\x02 \x01(\tR\x06sha256\"R\n" +
+	"\x1aGetProcessingStatusRequest\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"u\n" +
+	"\x0fProcessorResult\x12\x1c\n" +
+	"\tprocessor\x18\x01 \x01(\tR\tprocessor\x12.\n" +
+	"\x05state\x18\x02 \x01(\x0e2\x18.user.v1.ProcessingStateR\x05state\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\xb2\x01\n" +
+	"\x10ProcessingStatus\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12.\n" +
+	"\x05state\x18\x02 \x01(\x0e2\x18.user.v1.ProcessingStateR\x05state\x122\n" +
+	"\aresults\x18\x03 \x03(\v2\x18.user.v1.ProcessorResultR\aresults\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\x03R\tupdatedAt*v\n" +
 	"\n" +
 	"UserStatus\x12\x1b\n" +
 	"\x17USER_STATUS_UNSPECIFIED\x10\x00\x12\x16\n" +
@@ -846,13 +2985,47 @@ const file_proto_user_v1_user_proto_rawDesc = "" +
 	"\x16NOTIFICATION_TYPE_INFO\x10\x01\x12\x1d\n" +
 	"\x19NOTIFICATION_TYPE_WARNING\x10\x02\x12\x1b\n" +
 	"\x17NOTIFICATION_TYPE_ERROR\x10\x03\x12\x1d\n" +
-	"\x19NOTIFICATION_TYPE_SUCCESS\x10\x042\xbc\x02\n" +
+	"\x19NOTIFICATION_TYPE_SUCCESS\x10\x04*\x88\x01\n" +
+	"\rPublishStatus\x12\x1e\n" +
+	"\x1aPUBLISH_STATUS_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17PUBLISH_STATUS_ACCEPTED\x10\x01\x12\x1f\n" +
+	"\x1bPUBLISH_STATUS_DEDUPLICATED\x10\x02\x12\x19\n" +
+	"\x15PUBLISH_STATUS_QUEUED\x10\x03*\x88\x01\n" +
+	"\x14NotificationJobState\x12&\n" +
+	"\"NOTIFICATION_JOB_STATE_UNSPECIFIED\x10\x00\x12\"\n" +
+	"\x1eNOTIFICATION_JOB_STATE_RUNNING\x10\x01\x12$\n" +
+	" NOTIFICATION_JOB_STATE_COMPLETED\x10\x02*e\n" +
+	"\vUploadState\x12\x1c\n" +
+	"\x18UPLOAD_STATE_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18UPLOAD_STATE_IN_PROGRESS\x10\x01\x12\x1a\n" +
+	"\x16UPLOAD_STATE_COMPLETED\x10\x02*\xca\x01\n" +
+	"\x0fProcessingState\x12 \n" +
+	"\x1cPROCESSING_STATE_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18PROCESSING_STATE_PENDING\x10\x01\x12\x1c\n" +
+	"\x18PROCESSING_STATE_RUNNING\x10\x02\x12\x1e\n" +
+	"\x1aPROCESSING_STATE_SUCCEEDED\x10\x03\x12\x1b\n" +
+	"\x17PROCESSING_STATE_FAILED\x10\x04\x12\x1c\n" +
+	"\x18PROCESSING_STATE_SKIPPED\x10\x052\x9d\n" +
+	"\n" +
 	"\vUserService\x12<\n" +
 	"\aGetUser\x12\x17.user.v1.GetUserRequest\x1a\x18.user.v1.GetUserResponse\x12E\n" +
 	"\n" +
 	"CreateUser\x12\x1a.user.v1.CreateUSerRequest\x1a\x1b.user.v1.CreateUserResponse\x12S\n" +
 	"\x13StreamNotifications\x12#.user.v1.StreamNotificationsRequest\x1a\x15.user.v1.Notification0\x01\x12S\n" +
-	"\x0eUploadUserData\x12\x1e.user.v1.UploadUserDataRequest\x1a\x1f.user.v1.UploadUserDataResponse(\x01B\x15Z\x13gen/go/user/v1/userb\x06proto3"
+	"\x0eUploadUserData\x12\x1e.user.v1.UploadUserDataRequest\x1a\x1f.user.v1.UploadUserDataResponse(\x01\x12Y\n" +
+	"\x1aUploadUserDataWithProgress\x12\x1e.user.v1.UploadUserDataRequest\x1a\x17.user.v1.UploadProgress(\x010\x01\x12N\n" +
+	"\x10DownloadUserData\x12 .user.v1.DownloadUserDataRequest\x1a\x16.user.v1.UserDataChunk0\x01\x12T\n" +
+	"\x0fGetUploadStatus\x12\x1f.user.v1.GetUploadStatusRequest\x1a .user.v1.GetUploadStatusResponse\x12K\n" +
+	"\fGetUserQuota\x12\x1c.user.v1.GetUserQuotaRequest\x1a\x1d.user.v1.GetUserQuotaResponse\x12H\n" +
+	"\vListUploads\x12\x1b.user.v1.ListUploadsRequest\x1a\x1c.user.v1.ListUploadsResponse\x12K\n" +
+	"\fDeleteUpload\x12\x1c.user.v1.DeleteUploadRequest\x1a\x1d.user.v1.DeleteUploadResponse\x12Q\n" +
+	"\x0eInitiateUpload\x12\x1e.user.v1.InitiateUploadRequest\x1a\x1f.user.v1.InitiateUploadResponse\x12G\n" +
+	"\n" +
+	"UploadPart\x12\x1a.user.v1.UploadPartRequest\x1a\x1b.user.v1.UploadPartResponse(\x01\x12Q\n" +
+	"\x0eCompleteUpload\x12\x1e.user.v1.CompleteUploadRequest\x1a\x1f.user.v1.UploadUserDataResponse\x12U\n" +
+	"\x13GetProcessingStatus\x12#.user.v1.GetProcessingStatusRequest\x1a\x19.user.v1.ProcessingStatus\x12`\n" +
+	"\x13PublishNotification\x12#.user.v1.PublishNotificationRequest\x1a$.user.v1.PublishNotificationResponse\x12R\n" +
+	"\x12GetNotificationJob\x12\".user.v1.GetNotificationJobRequest\x1a\x18.user.v1.NotificationJobB\x15Z\x13gen/go/user/v1/userb\x06proto3"
 
 var (
 	file_proto_user_v1_user_proto_rawDescOnce sync.Once
@@ -866,43 +3039,114 @@ func file_proto_user_v1_user_proto_rawDescGZIP() []byte {
 	return file_proto_user_v1_user_proto_rawDescData
 }
 
-var file_proto_user_v1_user_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_proto_user_v1_user_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_proto_user_v1_user_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_proto_user_v1_user_proto_msgTypes = make([]protoimpl.MessageInfo, 38)
 var file_proto_user_v1_user_proto_goTypes = []any{
-	(UserStatus)(0),                    // 0: user.v1.UserStatus
-	(NotificationType)(0),              // 1: user.v1.NotificationType
-	(*GetUserRequest)(nil),             // 2: user.v1.GetUserRequest
-	(*GetUserResponse)(nil),            // 3: user.v1.GetUserResponse
-	(*CreateUSerRequest)(nil),          // 4: user.v1.CreateUSerRequest
-	(*CreateUserResponse)(nil),         // 5: user.v1.CreateUserResponse
-	(*User)(nil),                       // 6: user.v1.User
-	(*StreamNotificationsRequest)(nil), // 7: user.v1.StreamNotificationsRequest
-	(*Notification)(nil),               // 8: user.v1.Notification
-	(*UploadUserDataRequest)(nil),      // 9: user.v1.UploadUserDataRequest
-	(*UserMetadata)(nil),               // 10: user.v1.UserMetadata
-	(*UserDataChunk)(nil),              // 11: user.v1.UserDataChunk
-	(*UploadUserDataResponse)(nil),     // 12: user.v1.UploadUserDataResponse
+	(UserStatus)(0),                     // 0: user.v1.UserStatus
+	(NotificationType)(0),               // 1: user.v1.NotificationType
+	(PublishStatus)(0),                  // 2: user.v1.PublishStatus
+	(NotificationJobState)(0),           // 3: user.v1.NotificationJobState
+	(UploadState)(0),                    // 4: user.v1.UploadState
+	(ProcessingState)(0),                // 5: user.v1.ProcessingState
+	(*GetUserRequest)(nil),              // 6: user.v1.GetUserRequest
+	(*GetUserResponse)(nil),             // 7: user.v1.GetUserResponse
+	(*CreateUSerRequest)(nil),           // 8: user.v1.CreateUSerRequest
+	(*CreateUserResponse)(nil),          // 9: user.v1.CreateUserResponse
+	(*User)(nil),                        // 10: user.v1.User
+	(*StreamNotificationsRequest)(nil),  // 11: user.v1.StreamNotificationsRequest
+	(*Notification)(nil),                // 12: user.v1.Notification
+	(*PublishNotificationRequest)(nil),  // 13: user.v1.PublishNotificationRequest
+	(*NotificationAudience)(nil),        // 14: user.v1.NotificationAudience
+	(*UserSegment)(nil),                 // 15: user.v1.UserSegment
+	(*PublishNotificationResponse)(nil), // 16: user.v1.PublishNotificationResponse
+	(*GetNotificationJobRequest)(nil),   // 17: user.v1.GetNotificationJobRequest
+	(*NotificationJob)(nil),             // 18: user.v1.NotificationJob
+	(*UploadUserDataRequest)(nil),       // 19: user.v1.UploadUserDataRequest
+	(*UserMetadata)(nil),                // 20: user.v1.UserMetadata
+	(*UserDataChunk)(nil),               // 21: user.v1.UserDataChunk
+	(*UploadUserDataResponse)(nil),      // 22: user.v1.UploadUserDataResponse
+	(*UploadProgress)(nil),              // 23: user.v1.UploadProgress
+	(*DownloadUserDataRequest)(nil),     // 24: user.v1.DownloadUserDataRequest
+	(*GetUploadStatusRequest)(nil),      // 25: user.v1.GetUploadStatusRequest
+	(*GetUploadStatusResponse)(nil),     // 26: user.v1.GetUploadStatusResponse
+	(*GetUserQuotaRequest)(nil),         // 27: user.v1.GetUserQuotaRequest
+	(*GetUserQuotaResponse)(nil),        // 28: user.v1.GetUserQuotaResponse
+	(*ListUploadsRequest)(nil),          // 29: user.v1.ListUploadsRequest
+	(*UploadInfo)(nil),                  // 30: user.v1.UploadInfo
+	(*ListUploadsResponse)(nil),         // 31: user.v1.ListUploadsResponse
+	(*DeleteUploadRequest)(nil),         // 32: user.v1.DeleteUploadRequest
+	(*DeleteUploadResponse)(nil),        // 33: user.v1.DeleteUploadResponse
+	(*InitiateUploadRequest)(nil),       // 34: user.v1.InitiateUploadRequest
+	(*InitiateUploadResponse)(nil),      // 35: user.v1.InitiateUploadResponse
+	(*UploadPartRequest)(nil),           // 36: user.v1.UploadPartRequest
+	(*UploadPartHeader)(nil),            // 37: user.v1.UploadPartHeader
+	(*UploadPartResponse)(nil),          // 38: user.v1.UploadPartResponse
+	(*CompleteUploadRequest)(nil),       // 39: user.v1.CompleteUploadRequest
+	(*CompletedPart)(nil),               // 40: user.v1.CompletedPart
+	(*GetProcessingStatusRequest)(nil),  // 41: user.v1.GetProcessingStatusRequest
+	(*ProcessorResult)(nil),             // 42: user.v1.ProcessorResult
+	(*ProcessingStatus)(nil),            // 43: user.v1.ProcessingStatus
 }
 var file_proto_user_v1_user_proto_depIdxs = []int32{
-	6,  // 0: user.v1.GetUserResponse.user:type_name -> user.v1.User
-	6,  // 1: user.v1.CreateUserResponse.user:type_name -> user.v1.User
+	10, // 0: user.v1.GetUserResponse.user:type_name -> user.v1.User
+	10, // 1: user.v1.CreateUserResponse.user:type_name -> user.v1.User
 	0,  // 2: user.v1.User.status:type_name -> user.v1.UserStatus
 	1,  // 3: user.v1.Notification.type:type_name -> user.v1.NotificationType
-	10, // 4: user.v1.UploadUserDataRequest.metadata:type_name -> user.v1.UserMetadata
-	11, // 5: user.v1.UploadUserDataRequest.chunk:type_name -> user.v1.UserDataChunk
-	2,  // 6: user.v1.UserService.GetUser:input_type -> user.v1.GetUserRequest
-	4,  // 7: user.v1.UserService.CreateUser:input_type -> user.v1.CreateUSerRequest
-	7,  // 8: user.v1.UserService.StreamNotifications:input_type -> user.v1.StreamNotificationsRequest
-	9,  // 9: user.v1.UserService.UploadUserData:input_type -> user.v1.UploadUserDataRequest
-	3,  // 10: user.v1.UserService.GetUser:output_type -> user.v1.GetUserResponse
-	5,  // 11: user.v1.UserService.CreateUser:output_type -> user.v1.CreateUserResponse
-	8,  // 12: user.v1.UserService.StreamNotifications:output_type -> user.v1.Notification
-	12, // 13: user.v1.UserService.UploadUserData:output_type -> user.v1.UploadUserDataResponse
-	10, // [10:14] is the sub-list for method output_type
-	6,  // [6:10] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	12, // 4: user.v1.PublishNotificationRequest.notification:type_name -> user.v1.Notification
+	14, // 5: user.v1.PublishNotificationRequest.audience:type_name -> user.v1.NotificationAudience
+	15, // 6: user.v1.NotificationAudience.segment:type_name -> user.v1.UserSegment
+	0,  // 7: user.v1.UserSegment.status:type_name -> user.v1.UserStatus
+	2,  // 8: user.v1.PublishNotificationResponse.status:type_name -> user.v1.PublishStatus
+	3,  // 9: user.v1.NotificationJob.state:type_name -> user.v1.NotificationJobState
+	20, // 10: user.v1.UploadUserDataRequest.metadata:type_name -> user.v1.UserMetadata
+	21, // 11: user.v1.UploadUserDataRequest.chunk:type_name -> user.v1.UserDataChunk
+	22, // 12: user.v1.UploadProgress.result:type_name -> user.v1.UploadUserDataResponse
+	4,  // 13: user.v1.GetUploadStatusResponse.state:type_name -> user.v1.UploadState
+	30, // 14: user.v1.ListUploadsResponse.uploads:type_name -> user.v1.UploadInfo
+	20, // 15: user.v1.InitiateUploadRequest.metadata:type_name -> user.v1.UserMetadata
+	37, // 16: user.v1.UploadPartRequest.header:type_name -> user.v1.UploadPartHeader
+	21, // 17: user.v1.UploadPartRequest.chunk:type_name -> user.v1.UserDataChunk
+	40, // 18: user.v1.CompleteUploadRequest.parts:type_name -> user.v1.CompletedPart
+	5,  // 19: user.v1.ProcessorResult.state:type_name -> user.v1.ProcessingState
+	5,  // 20: user.v1.ProcessingStatus.state:type_name -> user.v1.ProcessingState
+	42, // 21: user.v1.ProcessingStatus.results:type_name -> user.v1.ProcessorResult
+	6,  // 22: user.v1.UserService.GetUser:input_type -> user.v1.GetUserRequest
+	8,  // 23: user.v1.UserService.CreateUser:input_type -> user.v1.CreateUSerRequest
+	11, // 24: user.v1.UserService.StreamNotifications:input_type -> user.v1.StreamNotificationsRequest
+	19, // 25: user.v1.UserService.UploadUserData:input_type -> user.v1.UploadUserDataRequest
+	19, // 26: user.v1.UserService.UploadUserDataWithProgress:input_type -> user.v1.UploadUserDataRequest
+	24, // 27: user.v1.UserService.DownloadUserData:input_type -> user.v1.DownloadUserDataRequest
+	25, // 28: user.v1.UserService.GetUploadStatus:input_type -> user.v1.GetUploadStatusRequest
+	27, // 29: user.v1.UserService.GetUserQuota:input_type -> user.v1.GetUserQuotaRequest
+	29, // 30: user.v1.UserService.ListUploads:input_type -> user.v1.ListUploadsRequest
+	32, // 31: user.v1.UserService.DeleteUpload:input_type -> user.v1.DeleteUploadRequest
+	34, // 32: user.v1.UserService.InitiateUpload:input_type -> user.v1.InitiateUploadRequest
+	36, // 33: user.v1.UserService.UploadPart:input_type -> user.v1.UploadPartRequest
+	39, // 34: user.v1.UserService.CompleteUpload:input_type -> user.v1.CompleteUploadRequest
+	41, // 35: user.v1.UserService.GetProcessingStatus:input_type -> user.v1.GetProcessingStatusRequest
+	13, // 36: user.v1.UserService.PublishNotification:input_type -> user.v1.PublishNotificationRequest
+	17, // 37: user.v1.UserService.GetNotificationJob:input_type -> user.v1.GetNotificationJobRequest
+	7,  // 38: user.v1.UserService.GetUser:output_type -> user.v1.GetUserResponse
+	9,  // 39: user.v1.UserService.CreateUser:output_type -> user.v1.CreateUserResponse
+	12, // 40: user.v1.UserService.StreamNotifications:output_type -> user.v1.Notification
+	22, // 41: user.v1.UserService.UploadUserData:output_type -> user.v1.UploadUserDataResponse
+	23, // 42: user.v1.UserService.UploadUserDataWithProgress:output_type -> user.v1.UploadProgress
+	21, // 43: user.v1.UserService.DownloadUserData:output_type -> user.v1.UserDataChunk
+	26, // 44: user.v1.UserService.GetUploadStatus:output_type -> user.v1.GetUploadStatusResponse
+	28, // 45: user.v1.UserService.GetUserQuota:output_type -> user.v1.GetUserQuotaResponse
+	31, // 46: user.v1.UserService.ListUploads:output_type -> user.v1.ListUploadsResponse
+	33, // 47: user.v1.UserService.DeleteUpload:output_type -> user.v1.DeleteUploadResponse
+	35, // 48: user.v1.UserService.InitiateUpload:output_type -> user.v1.InitiateUploadResponse
+	38, // 49: user.v1.UserService.UploadPart:output_type -> user.v1.UploadPartResponse
+	22, // 50: user.v1.UserService.CompleteUpload:output_type -> user.v1.UploadUserDataResponse
+	43, // 51: user.v1.UserService.GetProcessingStatus:output_type -> user.v1.ProcessingStatus
+	16, // 52: user.v1.UserService.PublishNotification:output_type -> user.v1.PublishNotificationResponse
+	18, // 53: user.v1.UserService.GetNotificationJob:output_type -> user.v1.NotificationJob
+	38, // [38:54] is the sub-list for method output_type
+	22, // [22:38] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
 }
 
 func init() { file_proto_user_v1_user_proto_init() }
@@ -910,17 +3154,26 @@ func file_proto_user_v1_user_proto_init() {
 	if File_proto_user_v1_user_proto != nil {
 		return
 	}
-	file_proto_user_v1_user_proto_msgTypes[7].OneofWrappers = []any{
+	file_proto_user_v1_user_proto_msgTypes[8].OneofWrappers = []any{
+		(*NotificationAudience_AllUsers)(nil),
+		(*NotificationAudience_Segment)(nil),
+	}
+	file_proto_user_v1_user_proto_msgTypes[13].OneofWrappers = []any{
 		(*UploadUserDataRequest_Metadata)(nil),
 		(*UploadUserDataRequest_Chunk)(nil),
 	}
+	file_proto_user_v1_user_proto_msgTypes[15].OneofWrappers = []any{}
+	file_proto_user_v1_user_proto_msgTypes[30].OneofWrappers = []any{
+		(*UploadPartRequest_Header)(nil),
+		(*UploadPartRequest_Chunk)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_user_v1_user_proto_rawDesc), len(file_proto_user_v1_user_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   11,
+			NumEnums:      6,
+			NumMessages:   38,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,10 +19,22 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	UserService_GetUser_FullMethodName             = "/user.v1.UserService/GetUser"
-	UserService_CreateUser_FullMethodName          = "/user.v1.UserService/CreateUser"
-	UserService_StreamNotifications_FullMethodName = "/user.v1.UserService/StreamNotifications"
-	UserService_UploadUserData_FullMethodName      = "/user.v1.UserService/UploadUserData"
+	UserService_GetUser_FullMethodName                    = "/user.v1.UserService/GetUser"
+	UserService_CreateUser_FullMethodName                 = "/user.v1.UserService/CreateUser"
+	UserService_StreamNotifications_FullMethodName        = "/user.v1.UserService/StreamNotifications"
+	UserService_UploadUserData_FullMethodName             = "/user.v1.UserService/UploadUserData"
+	UserService_UploadUserDataWithProgress_FullMethodName = "/user.v1.UserService/UploadUserDataWithProgress"
+	UserService_DownloadUserData_FullMethodName           = "/user.v1.UserService/DownloadUserData"
+	UserService_GetUploadStatus_FullMethodName            = "/user.v1.UserService/GetUploadStatus"
+	UserService_GetUserQuota_FullMethodName               = "/user.v1.UserService/GetUserQuota"
+	UserService_ListUploads_FullMethodName                = "/user.v1.UserService/ListUploads"
+	UserService_DeleteUpload_FullMethodName               = "/user.v1.UserService/DeleteUpload"
+	UserService_InitiateUpload_FullMethodName             = "/user.v1.UserService/InitiateUpload"
+	UserService_UploadPart_FullMethodName                 = "/user.v1.UserService/UploadPart"
+	UserService_CompleteUpload_FullMethodName             = "/user.v1.UserService/CompleteUpload"
+	UserService_GetProcessingStatus_FullMethodName        = "/user.v1.UserService/GetProcessingStatus"
+	UserService_PublishNotification_FullMethodName        = "/user.v1.UserService/PublishNotification"
+	UserService_GetNotificationJob_FullMethodName         = "/user.v1.UserService/GetNotificationJob"
 )
 
 // UserServiceClient is the client API for UserService service.
@@ -35,10 +47,34 @@ type UserServiceClient interface {
 	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*GetUserResponse, error)
 	// Create a new user
 	CreateUser(ctx context.Context, in *CreateUSerRequest, opts ...grpc.CallOption) (*CreateUserResponse, error)
-	// Server-side streaming RPC
+	// Server-side streaming RPC: notifications published for the user, until the client disconnects
 	StreamNotifications(ctx context.Context, in *StreamNotificationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Notification], error)
 	// Client-side streaming RPC
 	UploadUserData(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadUserDataRequest, UploadUserDataResponse], error)
+	// Bidirectional upload that acknowledges committed progress while data is sent
+	UploadUserDataWithProgress(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[UploadUserDataRequest, UploadProgress], error)
+	// Server-side streaming download of a stored upload
+	DownloadUserData(ctx context.Context, in *DownloadUserDataRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[UserDataChunk], error)
+	// Get the committed progress of an upload, used to resume it
+	GetUploadStatus(ctx context.Context, in *GetUploadStatusRequest, opts ...grpc.CallOption) (*GetUploadStatusResponse, error)
+	// Get a user's upload storage quota and usage
+	GetUserQuota(ctx context.Context, in *GetUserQuotaRequest, opts ...grpc.CallOption) (*GetUserQuotaResponse, error)
+	// List a user's completed uploads, oldest first
+	ListUploads(ctx context.Context, in *ListUploadsRequest, opts ...grpc.CallOption) (*ListUploadsResponse, error)
+	// Delete a completed upload
+	DeleteUpload(ctx context.Context, in *DeleteUploadRequest, opts ...grpc.CallOption) (*DeleteUploadResponse, error)
+	// Start a multipart upload whose parts can be sent concurrently
+	InitiateUpload(ctx context.Context, in *InitiateUploadRequest, opts ...grpc.CallOption) (*InitiateUploadResponse, error)
+	// Client-side streaming of one part of a multipart upload
+	UploadPart(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadPartRequest, UploadPartResponse], error)
+	// Assemble the parts of a multipart upload into the final upload
+	CompleteUpload(ctx context.Context, in *CompleteUploadRequest, opts ...grpc.CallOption) (*UploadUserDataResponse, error)
+	// Get the result of the post-upload processors run on an upload
+	GetProcessingStatus(ctx context.Context, in *GetProcessingStatusRequest, opts ...grpc.CallOption) (*ProcessingStatus, error)
+	// Publish a notification to a user's subscribers
+	PublishNotification(ctx context.Context, in *PublishNotificationRequest, opts ...grpc.CallOption) (*PublishNotificationResponse, error)
+	// Get the progress of a broadcast or segment publish; jobs are forgotten an hour after they complete
+	GetNotificationJob(ctx context.Context, in *GetNotificationJobRequest, opts ...grpc.CallOption) (*NotificationJob, error)
 }
 
 type userServiceClient struct {
@@ -101,6 +137,141 @@ func (c *userServiceClient) UploadUserData(ctx context.Context, opts ...grpc.Cal
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type UserService_UploadUserDataClient = grpc.ClientStreamingClient[UploadUserDataRequest, UploadUserDataResponse]
 
+func (c *userServiceClient) UploadUserDataWithProgress(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[UploadUserDataRequest, UploadProgress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UserService_ServiceDesc.Streams[2], UserService_UploadUserDataWithProgress_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadUserDataRequest, UploadProgress]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserService_UploadUserDataWithProgressClient = grpc.BidiStreamingClient[UploadUserDataRequest, UploadProgress]
+
+func (c *userServiceClient) DownloadUserData(ctx context.Context, in *DownloadUserDataRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[UserDataChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UserService_ServiceDesc.Streams[3], UserService_DownloadUserData_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DownloadUserDataRequest, UserDataChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserService_DownloadUserDataClient = grpc.ServerStreamingClient[UserDataChunk]
+
+func (c *userServiceClient) GetUploadStatus(ctx context.Context, in *GetUploadStatusRequest, opts ...grpc.CallOption) (*GetUploadStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUploadStatusResponse)
+	err := c.cc.Invoke(ctx, UserService_GetUploadStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUserQuota(ctx context.Context, in *GetUserQuotaRequest, opts ...grpc.CallOption) (*GetUserQuotaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserQuotaResponse)
+	err := c.cc.Invoke(ctx, UserService_GetUserQuota_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListUploads(ctx context.Context, in *ListUploadsRequest, opts ...grpc.CallOption) (*ListUploadsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUploadsResponse)
+	err := c.cc.Invoke(ctx, UserService_ListUploads_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DeleteUpload(ctx context.Context, in *DeleteUploadRequest, opts ...grpc.CallOption) (*DeleteUploadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteUploadResponse)
+	err := c.cc.Invoke(ctx, UserService_DeleteUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) InitiateUpload(ctx context.Context, in *InitiateUploadRequest, opts ...grpc.CallOption) (*InitiateUploadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InitiateUploadResponse)
+	err := c.cc.Invoke(ctx, UserService_InitiateUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UploadPart(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadPartRequest, UploadPartResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UserService_ServiceDesc.Streams[4], UserService_UploadPart_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadPartRequest, UploadPartResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserService_UploadPartClient = grpc.ClientStreamingClient[UploadPartRequest, UploadPartResponse]
+
+func (c *userServiceClient) CompleteUpload(ctx context.Context, in *CompleteUploadRequest, opts ...grpc.CallOption) (*UploadUserDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UploadUserDataResponse)
+	err := c.cc.Invoke(ctx, UserService_CompleteUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetProcessingStatus(ctx context.Context, in *GetProcessingStatusRequest, opts ...grpc.CallOption) (*ProcessingStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProcessingStatus)
+	err := c.cc.Invoke(ctx, UserService_GetProcessingStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) PublishNotification(ctx context.Context, in *PublishNotificationRequest, opts ...grpc.CallOption) (*PublishNotificationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PublishNotificationResponse)
+	err := c.cc.Invoke(ctx, UserService_PublishNotification_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetNotificationJob(ctx context.Context, in *GetNotificationJobRequest, opts ...grpc.CallOption) (*NotificationJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NotificationJob)
+	err := c.cc.Invoke(ctx, UserService_GetNotificationJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility.
@@ -111,10 +282,34 @@ type UserServiceServer interface {
 	GetUser(context.Context, *GetUserRequest) (*GetUserResponse, error)
 	// Create a new user
 	CreateUser(context.Context, *CreateUSerRequest) (*CreateUserResponse, error)
-	// Server-side streaming RPC
+	// Server-side streaming RPC: notifications published for the user, until the client disconnects
 	StreamNotifications(*StreamNotificationsRequest, grpc.ServerStreamingServer[Notification]) error
 	// Client-side streaming RPC
 	UploadUserData(grpc.ClientStreamingServer[UploadUserDataRequest, UploadUserDataResponse]) error
+	// Bidirectional upload that acknowledges committed progress while data is sent
+	UploadUserDataWithProgress(grpc.BidiStreamingServer[UploadUserDataRequest, UploadProgress]) error
+	// Server-side streaming download of a stored upload
+	DownloadUserData(*DownloadUserDataRequest, grpc.ServerStreamingServer[UserDataChunk]) error
+	// Get the committed progress of an upload, used to resume it
+	GetUploadStatus(context.Context, *GetUploadStatusRequest) (*GetUploadStatusResponse, error)
+	// Get a user's upload storage quota and usage
+	GetUserQuota(context.Context, *GetUserQuotaRequest) (*GetUserQuotaResponse, error)
+	// List a user's completed uploads, oldest first
+	ListUploads(context.Context, *ListUploadsRequest) (*ListUploadsResponse, error)
+	// Delete a completed upload
+	DeleteUpload(context.Context, *DeleteUploadRequest) (*DeleteUploadResponse, error)
+	// Start a multipart upload whose parts can be sent concurrently
+	InitiateUpload(context.Context, *InitiateUploadRequest) (*InitiateUploadResponse, error)
+	// Client-side streaming of one part of a multipart upload
+	UploadPart(grpc.ClientStreamingServer[UploadPartRequest, UploadPartResponse]) error
+	// Assemble the parts of a multipart upload into the final upload
+	CompleteUpload(context.Context, *CompleteUploadRequest) (*UploadUserDataResponse, error)
+	// Get the result of the post-upload processors run on an upload
+	GetProcessingStatus(context.Context, *GetProcessingStatusRequest) (*ProcessingStatus, error)
+	// Publish a notification to a user's subscribers
+	PublishNotification(context.Context, *PublishNotificationRequest) (*PublishNotificationResponse, error)
+	// Get the progress of a broadcast or segment publish; jobs are forgotten an hour after they complete
+	GetNotificationJob(context.Context, *GetNotificationJobRequest) (*NotificationJob, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -137,6 +332,42 @@ func (UnimplementedUserServiceServer) StreamNotifications(*StreamNotificationsRe
 func (UnimplementedUserServiceServer) UploadUserData(grpc.ClientStreamingServer[UploadUserDataRequest, UploadUserDataResponse]) error {
 	return status.Error(codes.Unimplemented, "method UploadUserData not implemented")
 }
+func (UnimplementedUserServiceServer) UploadUserDataWithProgress(grpc.BidiStreamingServer[UploadUserDataRequest, UploadProgress]) error {
+	return status.Error(codes.Unimplemented, "method UploadUserDataWithProgress not implemented")
+}
+func (UnimplementedUserServiceServer) DownloadUserData(*DownloadUserDataRequest, grpc.ServerStreamingServer[UserDataChunk]) error {
+	return status.Error(codes.Unimplemented, "method DownloadUserData not implemented")
+}
+func (UnimplementedUserServiceServer) GetUploadStatus(context.Context, *GetUploadStatusRequest) (*GetUploadStatusResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetUploadStatus not implemented")
+}
+func (UnimplementedUserServiceServer) GetUserQuota(context.Context, *GetUserQuotaRequest) (*GetUserQuotaResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetUserQuota not implemented")
+}
+func (UnimplementedUserServiceServer) ListUploads(context.Context, *ListUploadsRequest) (*ListUploadsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListUploads not implemented")
+}
+func (UnimplementedUserServiceServer) DeleteUpload(context.Context, *DeleteUploadRequest) (*DeleteUploadResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteUpload not implemented")
+}
+func (UnimplementedUserServiceServer) InitiateUpload(context.Context, *InitiateUploadRequest) (*InitiateUploadResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method InitiateUpload not implemented")
+}
+func (UnimplementedUserServiceServer) UploadPart(grpc.ClientStreamingServer[UploadPartRequest, UploadPartResponse]) error {
+	return status.Error(codes.Unimplemented, "method UploadPart not implemented")
+}
+func (UnimplementedUserServiceServer) CompleteUpload(context.Context, *CompleteUploadRequest) (*UploadUserDataResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CompleteUpload not implemented")
+}
+func (UnimplementedUserServiceServer) GetProcessingStatus(context.Context, *GetProcessingStatusRequest) (*ProcessingStatus, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetProcessingStatus not implemented")
+}
+func (UnimplementedUserServiceServer) PublishNotification(context.Context, *PublishNotificationRequest) (*PublishNotificationResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PublishNotification not implemented")
+}
+func (UnimplementedUserServiceServer) GetNotificationJob(context.Context, *GetNotificationJobRequest) (*NotificationJob, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetNotificationJob not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
 
@@ -212,6 +443,193 @@ func _UserService_UploadUserData_Handler(srv interface{}, stream grpc.ServerStre
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type UserService_UploadUserDataServer = grpc.ClientStreamingServer[UploadUserDataRequest, UploadUserDataResponse]
 
+func _UserService_UploadUserDataWithProgress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(UserServiceServer).UploadUserDataWithProgress(&grpc.GenericServerStream[UploadUserDataRequest, UploadProgress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserService_UploadUserDataWithProgressServer = grpc.BidiStreamingServer[UploadUserDataRequest, UploadProgress]
+
+func _UserService_DownloadUserData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadUserDataRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UserServiceServer).DownloadUserData(m, &grpc.GenericServerStream[DownloadUserDataRequest, UserDataChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserService_DownloadUserDataServer = grpc.ServerStreamingServer[UserDataChunk]
+
+func _UserService_GetUploadStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUploadStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUploadStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetUploadStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUploadStatus(ctx, req.(*GetUploadStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetUserQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUserQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetUserQuota_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUserQuota(ctx, req.(*GetUserQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListUploads_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUploadsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListUploads(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ListUploads_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListUploads(ctx, req.(*ListUploadsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DeleteUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DeleteUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_DeleteUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DeleteUpload(ctx, req.(*DeleteUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_InitiateUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InitiateUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).InitiateUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_InitiateUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).InitiateUpload(ctx, req.(*InitiateUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UploadPart_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(UserServiceServer).UploadPart(&grpc.GenericServerStream[UploadPartRequest, UploadPartResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UserService_UploadPartServer = grpc.ClientStreamingServer[UploadPartRequest, UploadPartResponse]
+
+func _UserService_CompleteUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CompleteUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_CompleteUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CompleteUpload(ctx, req.(*CompleteUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetProcessingStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProcessingStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetProcessingStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetProcessingStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetProcessingStatus(ctx, req.(*GetProcessingStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_PublishNotification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishNotificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).PublishNotification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_PublishNotification_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).PublishNotification(ctx, req.(*PublishNotificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetNotificationJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNotificationJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetNotificationJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetNotificationJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetNotificationJob(ctx, req.(*GetNotificationJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -227,6 +645,42 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateUser",
 			Handler:    _UserService_CreateUser_Handler,
 		},
+		{
+			MethodName: "GetUploadStatus",
+			Handler:    _UserService_GetUploadStatus_Handler,
+		},
+		{
+			MethodName: "GetUserQuota",
+			Handler:    _UserService_GetUserQuota_Handler,
+		},
+		{
+			MethodName: "ListUploads",
+			Handler:    _UserService_ListUploads_Handler,
+		},
+		{
+			MethodName: "DeleteUpload",
+			Handler:    _UserService_DeleteUpload_Handler,
+		},
+		{
+			MethodName: "InitiateUpload",
+			Handler:    _UserService_InitiateUpload_Handler,
+		},
+		{
+			MethodName: "CompleteUpload",
+			Handler:    _UserService_CompleteUpload_Handler,
+		},
+		{
+			MethodName: "GetProcessingStatus",
+			Handler:    _UserService_GetProcessingStatus_Handler,
+		},
+		{
+			MethodName: "PublishNotification",
+			Handler:    _UserService_PublishNotification_Handler,
+		},
+		{
+			MethodName: "GetNotificationJob",
+			Handler:    _UserService_GetNotificationJob_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -239,6 +693,22 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _UserService_UploadUserData_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "UploadUserDataWithProgress",
+			Handler:       _UserService_UploadUserDataWithProgress_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadUserData",
+			Handler:       _UserService_DownloadUserData_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UploadPart",
+			Handler:       _UserService_UploadPart_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "proto/user/v1/user.proto",
 }
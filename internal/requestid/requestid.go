@@ -0,0 +1,136 @@
+// Package requestid carries the x-request-id metadata that ties a client call
+// to the server's log lines and errors.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Header is the metadata key the ID travels under, in both directions
+const Header = "x-request-id"
+
+// maxLength bounds IDs accepted from callers, which end up in every log line
+const maxLength = 128
+
+// New returns a random request ID
+func New() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return "req_" + hex.EncodeToString(b)
+}
+
+// Valid reports whether id is short and made only of visible ASCII characters
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+type contextKey struct{}
+
+// NewContext returns a context carrying id
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID in ctx, or ""
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// FromIncoming returns the request ID a caller sent, if it is valid
+func FromIncoming(ctx context.Context) (string, bool) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if ids := md.Get(Header); len(ids) > 0 && Valid(ids[0]) {
+		return ids[0], true
+	}
+	return "", false
+}
+
+// FromError returns the request ID the server attached to err as a RequestInfo detail, or ""
+func FromError(err error) string {
+	st, ok := status.FromError(err)
+	if !ok {
+		return ""
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RequestInfo); ok {
+			return info.GetRequestId()
+		}
+	}
+	return ""
+}
+
+// UnaryClient is a grpc.UnaryClientInterceptor that sends a request ID with every call
+// and logs it when the call fails
+func UnaryClient(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx, id := outgoing(ctx)
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	logFailure(method, id, err)
+	return err
+}
+
+// StreamClient is a grpc.StreamClientInterceptor that sends a request ID with every stream
+// and logs it when the stream fails
+func StreamClient(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	ctx, id := outgoing(ctx)
+	cs, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		logFailure(method, id, err)
+		return nil, err
+	}
+	return &loggedClientStream{ClientStream: cs, method: method, id: id}, nil
+}
+
+// outgoing makes sure the outgoing metadata carries a request ID, reusing one already
+// set in the metadata or the context
+func outgoing(ctx context.Context) (context.Context, string) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if ids := md.Get(Header); len(ids) > 0 {
+		return ctx, ids[0]
+	}
+	id := FromContext(ctx)
+	if id == "" {
+		id = New()
+	}
+	return metadata.AppendToOutgoingContext(ctx, Header, id), id
+}
+
+// logFailure prints the request ID of a failed call so it can be looked up in the server logs.
+// Cancellation is the caller's own doing and isn't logged.
+func logFailure(method, id string, err error) {
+	if err == nil || status.Code(err) == codes.Canceled {
+		return
+	}
+	log.Printf("🔎 %s failed with %s, request id %s", method, status.Code(err), id)
+}
+
+type loggedClientStream struct {
+	grpc.ClientStream
+	method, id string
+}
+
+func (s *loggedClientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != io.EOF {
+		logFailure(s.method, s.id, err)
+	}
+	return err
+}
@@ -0,0 +1,22 @@
+// Package rpcstatus converts handler errors to gRPC statuses the way clients
+// receive them, so logs, metrics and traces agree on the code.
+package rpcstatus
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Convert is status.Convert, except that context errors become Canceled or
+// DeadlineExceeded instead of Unknown, as gRPC reports them to the client
+func Convert(err error) *status.Status {
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+	return status.FromContextError(err)
+}
+
+// Code returns the code of err as Convert reports it
+func Code(err error) codes.Code {
+	return Convert(err).Code()
+}
@@ -0,0 +1,33 @@
+package rpcstatus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{name: "nil", err: nil, want: codes.OK},
+		{name: "status", err: status.Error(codes.NotFound, "gone"), want: codes.NotFound},
+		{name: "canceled", err: context.Canceled, want: codes.Canceled},
+		{name: "deadline", err: context.DeadlineExceeded, want: codes.DeadlineExceeded},
+		{name: "wrapped canceled", err: fmt.Errorf("recv: %w", context.Canceled), want: codes.Canceled},
+		{name: "status wins over context", err: status.Error(codes.Internal, context.Canceled.Error()), want: codes.Internal},
+		{name: "plain error", err: errors.New("boom"), want: codes.Unknown},
+	}
+
+	for _, tt := range tests {
+		if got := Code(tt.err); got != tt.want {
+			t.Errorf("%s: Code(%v) = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
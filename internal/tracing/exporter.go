@@ -0,0 +1,92 @@
+package tracing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// SpanData is a finished span as handed to an exporter
+type SpanData struct {
+	Service      string         `json:"service"`
+	TraceID      string         `json:"trace_id"`
+	SpanID       string         `json:"span_id"`
+	ParentSpanID string         `json:"parent_span_id,omitempty"`
+	Name         string         `json:"name"`
+	Kind         SpanKind       `json:"kind"`
+	StartTime    time.Time      `json:"start_time"`
+	EndTime      time.Time      `json:"end_time"`
+	DurationMS   float64        `json:"duration_ms"`
+	Attributes   map[string]any `json:"attributes,omitempty"`
+	Status       SpanStatus     `json:"status"`
+}
+
+// SpanStatus is the gRPC status the spanned operation ended with
+type SpanStatus struct {
+	Code    string `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// Exporter sends finished spans somewhere; it must be safe for concurrent use
+type Exporter interface {
+	ExportSpan(span *SpanData) error
+	// Shutdown flushes buffered spans and releases the exporter
+	Shutdown() error
+}
+
+// NewExporter returns the exporter named by kind: "none", "stdout", or "file" writing to path.
+// "none" returns a nil Exporter.
+func NewExporter(kind, path string) (Exporter, error) {
+	switch kind {
+	case "", "none":
+		return nil, nil
+	case "stdout":
+		return NewJSONExporter(os.Stdout), nil
+	case "file":
+		return NewFileExporter(path)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q, use none, stdout or file", kind)
+	}
+}
+
+// jsonExporter writes each span as one line of JSON
+type jsonExporter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	closer  io.Closer // nil when the writer isn't ours to close
+}
+
+// NewJSONExporter writes spans to w as JSON lines
+func NewJSONExporter(w io.Writer) Exporter {
+	return &jsonExporter{encoder: json.NewEncoder(w)}
+}
+
+// NewFileExporter appends spans to the file at path as JSON lines
+func NewFileExporter(path string) (Exporter, error) {
+	if path == "" {
+		return nil, fmt.Errorf("trace file path is required")
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open trace file: %w", err)
+	}
+	return &jsonExporter{encoder: json.NewEncoder(f), closer: f}, nil
+}
+
+func (e *jsonExporter) ExportSpan(span *SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.encoder.Encode(span)
+}
+
+func (e *jsonExporter) Shutdown() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closer == nil {
+		return nil
+	}
+	return e.closer.Close()
+}
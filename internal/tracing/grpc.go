@@ -0,0 +1,202 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// traceparentHeader is the W3C trace context key, carried as gRPC metadata
+const traceparentHeader = "traceparent"
+
+// UnaryServer is a grpc.UnaryServerInterceptor that spans each call
+func (t *Tracer) UnaryServer(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, span := t.startServer(ctx, info.FullMethod)
+	resp, err := handler(ctx, req)
+	span.End(err)
+	return resp, err
+}
+
+// StreamServer is a grpc.StreamServerInterceptor that spans each stream and every message on it
+func (t *Tracer) StreamServer(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, span := t.startServer(ss.Context(), info.FullMethod)
+	err := handler(srv, &tracedServerStream{ServerStream: ss, ctx: ctx, messages: messageSpans{tracer: t, ctx: ctx, name: span.name}})
+	span.End(err)
+	return err
+}
+
+// UnaryClient is a grpc.UnaryClientInterceptor that spans each call and sends its traceparent
+func (t *Tracer) UnaryClient(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx, span := t.startClient(ctx, method, cc)
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	span.End(err)
+	return err
+}
+
+// StreamClient is a grpc.StreamClientInterceptor that spans each stream and every message on it.
+// The stream span ends when the response stream finishes or ctx is done.
+func (t *Tracer) StreamClient(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	ctx, span := t.startClient(ctx, method, cc)
+	cs, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		span.End(err)
+		return nil, err
+	}
+
+	stream := &tracedClientStream{
+		ClientStream:  cs,
+		span:          span,
+		serverStreams: desc.ServerStreams,
+		messages:      messageSpans{tracer: t, ctx: ctx, name: span.name},
+		done:          make(chan struct{}),
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			stream.finish(status.FromContextError(ctx.Err()).Err())
+		case <-stream.done:
+		}
+	}()
+	return stream, nil
+}
+
+func (t *Tracer) startServer(ctx context.Context, fullMethod string) (context.Context, *Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(traceparentHeader); len(values) > 0 {
+		if sc, ok := ParseTraceparent(values[0]); ok {
+			ctx = ContextWithRemoteParent(ctx, sc)
+		}
+	}
+
+	ctx, span := t.Start(ctx, strings.TrimPrefix(fullMethod, "/"), KindServer)
+	setRPCAttributes(span, fullMethod)
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		span.SetAttribute("net.peer.address", p.Addr.String())
+	}
+	return ctx, span
+}
+
+func (t *Tracer) startClient(ctx context.Context, fullMethod string, cc *grpc.ClientConn) (context.Context, *Span) {
+	ctx, span := t.Start(ctx, strings.TrimPrefix(fullMethod, "/"), KindClient)
+	setRPCAttributes(span, fullMethod)
+	span.SetAttribute("server.address", cc.Target())
+	// Replace rather than append so a retried call doesn't carry two parents
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	md.Set(traceparentHeader, span.SpanContext().Traceparent())
+	return metadata.NewOutgoingContext(ctx, md), span
+}
+
+// setRPCAttributes splits "/package.Service/Method" into the OpenTelemetry RPC attributes
+func setRPCAttributes(span *Span, fullMethod string) {
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	span.SetAttribute("rpc.system", "grpc")
+	span.SetAttribute("rpc.service", service)
+	span.SetAttribute("rpc.method", method)
+}
+
+// messageSpans records one child span per message sent or received on a stream
+type messageSpans struct {
+	tracer         *Tracer
+	ctx            context.Context // carries the stream span
+	name           string
+	sent, received atomic.Int64
+}
+
+func (m *messageSpans) record(direction string, msg any, start time.Time, err error) {
+	var id int64
+	if direction == "SENT" {
+		id = m.sent.Add(1)
+	} else {
+		id = m.received.Add(1)
+	}
+
+	span := m.tracer.startAt(m.ctx, m.name+"/"+strings.ToLower(direction), KindInternal, start)
+	span.SetAttribute("message.type", direction)
+	span.SetAttribute("message.id", id)
+	if pm, ok := msg.(proto.Message); ok && err == nil {
+		span.SetAttribute("message.uncompressed_size", proto.Size(pm))
+	}
+	span.End(err)
+}
+
+type tracedServerStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	messages messageSpans
+}
+
+func (s *tracedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *tracedServerStream) SendMsg(m any) error {
+	start := time.Now()
+	err := s.ServerStream.SendMsg(m)
+	s.messages.record("SENT", m, start, err)
+	return err
+}
+
+func (s *tracedServerStream) RecvMsg(m any) error {
+	start := time.Now()
+	err := s.ServerStream.RecvMsg(m)
+	if err != io.EOF {
+		s.messages.record("RECEIVED", m, start, err)
+	}
+	return err
+}
+
+type tracedClientStream struct {
+	grpc.ClientStream
+	span          *Span
+	serverStreams bool
+	messages      messageSpans
+
+	once sync.Once
+	done chan struct{}
+}
+
+// finish ends the stream span once, with the first outcome observed
+func (s *tracedClientStream) finish(err error) {
+	s.once.Do(func() {
+		close(s.done)
+		s.span.End(err)
+	})
+}
+
+func (s *tracedClientStream) SendMsg(m any) error {
+	start := time.Now()
+	err := s.ClientStream.SendMsg(m)
+	// A failed send reports io.EOF; the real status comes from RecvMsg
+	if err != io.EOF {
+		s.messages.record("SENT", m, start, err)
+	}
+	return err
+}
+
+func (s *tracedClientStream) RecvMsg(m any) error {
+	start := time.Now()
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == io.EOF:
+		s.finish(nil)
+	case err != nil:
+		s.messages.record("RECEIVED", m, start, err)
+		s.finish(err)
+	default:
+		s.messages.record("RECEIVED", m, start, nil)
+		if !s.serverStreams {
+			s.finish(nil)
+		}
+	}
+	return err
+}
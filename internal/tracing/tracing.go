@@ -0,0 +1,222 @@
+// Package tracing records spans for gRPC calls and propagates them between
+// processes with the W3C trace context traceparent header.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"grpc-go-learning/internal/rpcstatus"
+
+	"google.golang.org/grpc/codes"
+)
+
+// SpanKind describes the role of a span in an RPC
+type SpanKind string
+
+const (
+	KindServer   SpanKind = "server"
+	KindClient   SpanKind = "client"
+	KindInternal SpanKind = "internal"
+)
+
+// SpanContext identifies a span within a trace
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Sampled bool
+}
+
+// IsValid reports whether both IDs are set; all-zero IDs are invalid per the spec
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != [16]byte{} && sc.SpanID != [8]byte{}
+}
+
+// Traceparent formats sc as a version 00 traceparent header value
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return "00-" + hex.EncodeToString(sc.TraceID[:]) + "-" + hex.EncodeToString(sc.SpanID[:]) + "-" + flags
+}
+
+// ParseTraceparent parses a traceparent header value. Versions after 00 are
+// accepted as long as they start with the fields version 00 defines.
+func ParseTraceparent(value string) (SpanContext, bool) {
+	var sc SpanContext
+
+	value = strings.TrimSpace(value)
+	// The spec only allows lowercase hex, which hex.Decode alone doesn't enforce
+	if strings.ToLower(value) != value {
+		return sc, false
+	}
+	parts := strings.Split(value, "-")
+	if len(parts) < 4 {
+		return sc, false
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if len(version) != 2 || version == "ff" || (version == "00" && len(parts) != 4) {
+		return sc, false
+	}
+	if _, err := hex.DecodeString(version); err != nil {
+		return sc, false
+	}
+	if len(traceID) != 32 || len(spanID) != 16 || len(flags) != 2 {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.TraceID[:], []byte(traceID)); err != nil {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(spanID)); err != nil {
+		return sc, false
+	}
+	var flagByte [1]byte
+	if _, err := hex.Decode(flagByte[:], []byte(flags)); err != nil {
+		return sc, false
+	}
+	sc.Sampled = flagByte[0]&1 == 1
+
+	return sc, sc.IsValid()
+}
+
+// Tracer creates spans and hands finished ones to an exporter
+type Tracer struct {
+	service  string
+	exporter Exporter // nil: spans are propagated but not recorded
+}
+
+// NewTracer returns a tracer for service; exporter may be nil
+func NewTracer(service string, exporter Exporter) *Tracer {
+	return &Tracer{service: service, exporter: exporter}
+}
+
+// Shutdown flushes and closes the exporter
+func (t *Tracer) Shutdown() error {
+	if t.exporter == nil {
+		return nil
+	}
+	return t.exporter.Shutdown()
+}
+
+// Start begins a span that is a child of the span or remote parent in ctx,
+// or the root of a new trace, and returns a context carrying it
+func (t *Tracer) Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	span := t.startAt(ctx, name, kind, time.Now())
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// startAt begins a span with an explicit start time, without adding it to a context
+func (t *Tracer) startAt(ctx context.Context, name string, kind SpanKind, start time.Time) *Span {
+	span := &Span{
+		tracer: t,
+		name:   name,
+		kind:   kind,
+		start:  start,
+		attrs:  make(map[string]any),
+	}
+
+	if parent, ok := parentFromContext(ctx); ok {
+		span.sc.TraceID = parent.TraceID
+		span.sc.Sampled = parent.Sampled
+		span.parentID = parent.SpanID
+	} else {
+		rand.Read(span.sc.TraceID[:])
+		span.sc.Sampled = true
+	}
+	rand.Read(span.sc.SpanID[:])
+	return span
+}
+
+type spanKey struct{}
+type remoteParentKey struct{}
+
+// SpanFromContext returns the span started in ctx, or nil
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// ContextWithRemoteParent makes sc, received from another process, the parent of spans started in ctx
+func ContextWithRemoteParent(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteParentKey{}, sc)
+}
+
+func parentFromContext(ctx context.Context) (SpanContext, bool) {
+	if span := SpanFromContext(ctx); span != nil {
+		return span.sc, true
+	}
+	sc, ok := ctx.Value(remoteParentKey{}).(SpanContext)
+	return sc, ok
+}
+
+// Span is one timed operation in a trace
+type Span struct {
+	tracer   *Tracer
+	sc       SpanContext
+	parentID [8]byte
+	name     string
+	kind     SpanKind
+	start    time.Time
+
+	mu    sync.Mutex
+	attrs map[string]any
+	ended bool
+}
+
+// SpanContext returns the IDs that identify s to other processes
+func (s *Span) SpanContext() SpanContext {
+	return s.sc
+}
+
+// TraceID returns the hex-encoded trace ID, for correlating logs
+func (s *Span) TraceID() string {
+	return hex.EncodeToString(s.sc.TraceID[:])
+}
+
+// SetAttribute records a key-value pair on the span
+func (s *Span) SetAttribute(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attrs[key] = value
+}
+
+// End finishes the span with the status of err and exports it; later calls do nothing
+func (s *Span) End(err error) {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	end := time.Now()
+
+	st := rpcstatus.Convert(err)
+	data := &SpanData{
+		Service:    s.tracer.service,
+		TraceID:    hex.EncodeToString(s.sc.TraceID[:]),
+		SpanID:     hex.EncodeToString(s.sc.SpanID[:]),
+		Name:       s.name,
+		Kind:       s.kind,
+		StartTime:  s.start,
+		EndTime:    end,
+		DurationMS: float64(end.Sub(s.start).Microseconds()) / 1000,
+		Attributes: s.attrs,
+		Status:     SpanStatus{Code: st.Code().String()},
+	}
+	if s.parentID != [8]byte{} {
+		data.ParentSpanID = hex.EncodeToString(s.parentID[:])
+	}
+	if st.Code() != codes.OK {
+		data.Status.Message = st.Message()
+	}
+	s.mu.Unlock()
+
+	if s.tracer.exporter != nil && s.sc.Sampled {
+		s.tracer.exporter.ExportSpan(data)
+	}
+}
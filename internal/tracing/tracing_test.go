@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+	tests := []struct {
+		name        string
+		value       string
+		wantOK      bool
+		wantSampled bool
+	}{
+		{name: "sampled", value: "00-" + traceID + "-" + spanID + "-01", wantOK: true, wantSampled: true},
+		{name: "not sampled", value: "00-" + traceID + "-" + spanID + "-00", wantOK: true},
+		{name: "other flags ignored", value: "00-" + traceID + "-" + spanID + "-03", wantOK: true, wantSampled: true},
+		{name: "surrounding whitespace", value: " 00-" + traceID + "-" + spanID + "-01 ", wantOK: true, wantSampled: true},
+		{name: "future version with extra field", value: "01-" + traceID + "-" + spanID + "-01-what", wantOK: true, wantSampled: true},
+		{name: "version 00 with extra field", value: "00-" + traceID + "-" + spanID + "-01-what"},
+		{name: "forbidden version", value: "ff-" + traceID + "-" + spanID + "-01"},
+		{name: "non-hex version", value: "zz-" + traceID + "-" + spanID + "-01"},
+		{name: "uppercase hex", value: "00-4BF92F3577B34DA6A3CE929D0E0E4736-" + spanID + "-01"},
+		{name: "zero trace id", value: "00-00000000000000000000000000000000-" + spanID + "-01"},
+		{name: "zero span id", value: "00-" + traceID + "-0000000000000000-01"},
+		{name: "short trace id", value: "00-" + traceID[1:] + "-" + spanID + "-01"},
+		{name: "short span id", value: "00-" + traceID + "-" + spanID[1:] + "-01"},
+		{name: "non-hex flags", value: "00-" + traceID + "-" + spanID + "-0x"},
+		{name: "missing flags", value: "00-" + traceID + "-" + spanID},
+		{name: "empty", value: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, ok := ParseTraceparent(tt.value)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseTraceparent(%q) ok = %v, want %v", tt.value, ok, tt.wantOK)
+			}
+			if ok && sc.Sampled != tt.wantSampled {
+				t.Fatalf("ParseTraceparent(%q) sampled = %v, want %v", tt.value, sc.Sampled, tt.wantSampled)
+			}
+		})
+	}
+}
+
+func TestTraceparentRoundTrip(t *testing.T) {
+	for _, value := range []string{
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00",
+	} {
+		sc, ok := ParseTraceparent(value)
+		if !ok {
+			t.Fatalf("ParseTraceparent(%q) failed", value)
+		}
+		if got := sc.Traceparent(); got != value {
+			t.Fatalf("Traceparent() = %q, want %q", got, value)
+		}
+	}
+}
+
+func TestSpanEndStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{err: nil, want: "OK"},
+		{err: context.Canceled, want: "Canceled"},
+		{err: context.DeadlineExceeded, want: "DeadlineExceeded"},
+		{err: status.Error(codes.NotFound, "gone"), want: "NotFound"},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		tracer := NewTracer("test", NewJSONExporter(&out))
+		_, span := tracer.Start(context.Background(), "op", KindServer)
+		span.End(tt.err)
+
+		var data SpanData
+		if err := json.Unmarshal(out.Bytes(), &data); err != nil {
+			t.Fatalf("parse exported span %q: %v", out.String(), err)
+		}
+		if data.Status.Code != tt.want {
+			t.Errorf("End(%v) status = %s, want %s", tt.err, data.Status.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// callerKey is the context key holding the authenticated caller's user ID
+type callerKey struct{}
+
+// callerFromContext returns the authenticated caller's user ID. It is only set when
+// tls.caller_from_cert maps verified client certificates to users, so callers must
+// treat false as "unauthenticated" rather than "forbidden".
+func callerFromContext(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(callerKey{}).(string)
+	return userID, ok && userID != ""
+}
+
+// withCertCaller returns ctx carrying the common name of the client certificate verified
+// during the TLS handshake as the caller's user ID. Connections without a verified
+// certificate, allowed with client_auth optional, stay unauthenticated.
+func withCertCaller(ctx context.Context) context.Context {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return ctx
+	}
+	userID := info.State.VerifiedChains[0][0].Subject.CommonName
+	if userID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, callerKey{}, userID)
+}
+
+// certCallerUnary authenticates unary calls by their client certificate
+func certCallerUnary(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(withCertCaller(ctx), req)
+}
+
+// certCallerStream authenticates streams by their client certificate
+func certCallerStream(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &contextStream{ServerStream: ss, ctx: withCertCaller(ss.Context())})
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+
+	userv1 "grpc-go-learning/gen/go/user/v1/user"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// peerContext returns a context for a connection whose verified client certificate is
+// named commonName; an empty name means the client presented no certificate
+func peerContext(commonName string) context.Context {
+	var state tls.ConnectionState
+	if commonName != "" {
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+		state.VerifiedChains = [][]*x509.Certificate{{cert}}
+	}
+	return peer.NewContext(context.Background(), &peer.Peer{
+		Addr:     &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)},
+		AuthInfo: credentials.TLSInfo{State: state},
+	})
+}
+
+func TestWithCertCaller(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		wantCaller string // empty: unauthenticated
+	}{
+		{name: "verified certificate", ctx: peerContext("user_2"), wantCaller: "user_2"},
+		{name: "no certificate", ctx: peerContext("")},
+		{name: "no peer", ctx: context.Background()},
+		{name: "plaintext peer", ctx: peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{}})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caller, ok := callerFromContext(withCertCaller(tt.ctx))
+			if caller != tt.wantCaller || ok != (tt.wantCaller != "") {
+				t.Errorf("caller = %q, %v; want %q", caller, ok, tt.wantCaller)
+			}
+		})
+	}
+}
+
+func TestCertCallerCanOnlyUploadForThemselves(t *testing.T) {
+	tests := []struct {
+		name     string
+		cert     string // client certificate common name; empty for none
+		wantCode codes.Code
+	}{
+		{name: "same user", cert: testUserID, wantCode: codes.OK},
+		{name: "other user", cert: "user_2", wantCode: codes.PermissionDenied},
+		{name: "unauthenticated", wantCode: codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			req := &userv1.InitiateUploadRequest{
+				Metadata: &userv1.UserMetadata{UserId: testUserID, Filename: "file.bin", TotalSize: 3},
+			}
+			handler := func(ctx context.Context, req any) (any, error) {
+				return s.InitiateUpload(ctx, req.(*userv1.InitiateUploadRequest))
+			}
+
+			_, err := certCallerUnary(peerContext(tt.cert), req, &grpc.UnaryServerInfo{}, handler)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("InitiateUpload code = %v, want %v (%v)", code, tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestCertCallerStreamRejectsOtherUser(t *testing.T) {
+	s := newTestServer(t)
+	stream := &fakeUploadStream{
+		ctx: peerContext("user_2"),
+		requests: []*userv1.UploadUserDataRequest{{
+			Data: &userv1.UploadUserDataRequest_Metadata{
+				Metadata: &userv1.UserMetadata{UserId: testUserID, Filename: "file.bin", TotalSize: 3},
+			},
+		}},
+	}
+
+	// The handler sees the stream through the interceptor's context
+	handler := func(_ any, ss grpc.ServerStream) error {
+		stream.ctx = ss.Context()
+		return s.UploadUserData(stream)
+	}
+	err := certCallerStream(nil, stream, &grpc.StreamServerInfo{}, handler)
+	if code := status.Code(err); code != codes.PermissionDenied {
+		t.Fatalf("UploadUserData code = %v, want PermissionDenied (%v)", code, err)
+	}
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// defaultUploadDir is where the local blob store keeps uploaded data
+const defaultUploadDir = "data/uploads"
+
+// blobStore persists uploaded data addressed by the SHA-256 of its content.
+// Identical content is stored once and reference counted.
+type blobStore interface {
+	// Create starts a new blob; nothing is visible until Commit succeeds
+	Create() (blobWriter, error)
+	// Open returns a committed blob; the error wraps fs.ErrNotExist for unknown digests
+	Open(digest string) (io.ReadSeekCloser, error)
+	// Release drops one reference to digest, deleting the data when none remain
+	Release(digest string) error
+	// Reclaim sets the reference counts from the digests of the known uploads, one
+	// entry per upload, and deletes stored data none of them refers to
+	Reclaim(digests []string) (int, error)
+	// Check reports whether the store can currently accept new data
+	Check() error
+	// Close flushes stored data at shutdown, after the last upload stream has ended
+	Close() error
+}
+
+// blobWriter receives the bytes of a single blob
+type blobWriter interface {
+	Write(p []byte) (int, error)
+	// Digest returns the hex-encoded SHA-256 of everything written so far
+	Digest() string
+	// Commit flushes the data and stores it under its digest, or takes a
+	// reference to an existing copy of the same content
+	Commit() error
+	// Abort discards everything written so far
+	Abort() error
+}
+
+// localBlobStore keeps blobs as files named by digest under root/objects.
+// Reference counts live in memory alongside the upload index and are rebuilt
+// from it by Reclaim at startup.
+type localBlobStore struct {
+	root string
+
+	mu   sync.Mutex
+	refs map[string]int // digest -> number of uploads sharing it
+}
+
+func newLocalBlobStore(root string) (*localBlobStore, error) {
+	for _, dir := range []string{"tmp", "objects"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			return nil, fmt.Errorf("create upload directory: %w", err)
+		}
+	}
+	return &localBlobStore{
+		root: root,
+		refs: make(map[string]int),
+	}, nil
+}
+
+// path returns the location of digest, sharded by its first two characters
+func (b *localBlobStore) path(digest string) (string, error) {
+	digest, err := normalizeSHA256(digest)
+	if err != nil {
+		return "", fmt.Errorf("invalid blob digest: %w", err)
+	}
+	return filepath.Join(b.root, "objects", digest[:2], digest), nil
+}
+
+func (b *localBlobStore) Create() (blobWriter, error) {
+	// Write into tmp/ so readers never see a partial file
+	f, err := os.CreateTemp(filepath.Join(b.root, "tmp"), "blob-*.part")
+	if err != nil {
+		return nil, fmt.Errorf("create blob: %w", err)
+	}
+	return &localBlobWriter{store: b, file: f, hash: sha256.New()}, nil
+}
+
+func (b *localBlobStore) Open(digest string) (io.ReadSeekCloser, error) {
+	path, err := b.path(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open blob %s: %w", digest, err)
+	}
+	return f, nil
+}
+
+func (b *localBlobStore) Release(digest string) error {
+	path, err := b.path(digest)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.refs[digest] <= 0 {
+		return fmt.Errorf("release blob %s: no references held", digest)
+	}
+	b.refs[digest]--
+	if b.refs[digest] > 0 {
+		return nil
+	}
+
+	delete(b.refs, digest)
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("delete blob %s: %w", digest, err)
+	}
+	return nil
+}
+
+func (b *localBlobStore) Reclaim(digests []string) (int, error) {
+	refs := make(map[string]int)
+	for _, digest := range digests {
+		refs[digest]++
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refs = refs
+
+	var removed int
+	var errs []error
+	err := filepath.WalkDir(filepath.Join(b.root, "objects"), func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || refs[entry.Name()] > 0 {
+			return err
+		}
+		if err := os.Remove(path); err != nil {
+			errs = append(errs, fmt.Errorf("delete blob %s: %w", entry.Name(), err))
+			return nil
+		}
+		removed++
+		return nil
+	})
+	return removed, errors.Join(append(errs, err)...)
+}
+
+func (b *localBlobStore) Check() error {
+	f, err := os.CreateTemp(filepath.Join(b.root, "tmp"), "health-*")
+	if err != nil {
+		return fmt.Errorf("storage not writable: %w", err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// Close makes published blobs durable and removes the temporary files of
+// incomplete uploads, which can't be resumed once the in-memory sessions are gone
+func (b *localBlobStore) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var errs []error
+
+	// A rename is only durable once the directory holding it is synced
+	objects := filepath.Join(b.root, "objects")
+	shards, err := os.ReadDir(objects)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, shard := range shards {
+		if shard.IsDir() {
+			errs = append(errs, syncDir(filepath.Join(objects, shard.Name())))
+		}
+	}
+	errs = append(errs, syncDir(objects))
+
+	tmp := filepath.Join(b.root, "tmp")
+	parts, err := os.ReadDir(tmp)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, part := range parts {
+		if err := os.Remove(filepath.Join(tmp, part.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
+
+// publish moves a finished temporary file to the location for digest, or
+// drops it when that content is already stored, and takes a reference
+func (b *localBlobStore) publish(tmpPath, digest string) error {
+	path, err := b.path(digest)
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, err := os.Stat(path); err == nil {
+		// Same content already stored: share it
+		os.Remove(tmpPath)
+		b.refs[digest]++
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("publish blob: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("publish blob: %w", err)
+	}
+	b.refs[digest]++
+	return nil
+}
+
+// localBlobWriter writes to a temporary file that is moved into place on Commit
+type localBlobWriter struct {
+	store *localBlobStore
+	file  *os.File
+	hash  hash.Hash
+	done  bool
+}
+
+func (w *localBlobWriter) Write(p []byte) (int, error) {
+	n, err := w.file.Write(p)
+	w.hash.Write(p[:n])
+	return n, err
+}
+
+func (w *localBlobWriter) Digest() string {
+	return hex.EncodeToString(w.hash.Sum(nil))
+}
+
+func (w *localBlobWriter) Commit() error {
+	if w.done {
+		return errors.New("blob already committed or aborted")
+	}
+	w.done = true
+
+	if err := w.file.Sync(); err != nil {
+		w.discard()
+		return fmt.Errorf("sync blob: %w", err)
+	}
+	if err := w.file.Close(); err != nil {
+		os.Remove(w.file.Name())
+		return fmt.Errorf("close blob: %w", err)
+	}
+	return w.store.publish(w.file.Name(), w.Digest())
+}
+
+func (w *localBlobWriter) Abort() error {
+	if w.done {
+		return nil
+	}
+	w.done = true
+	return w.discard()
+}
+
+func (w *localBlobWriter) discard() error {
+	w.file.Close()
+	return os.Remove(w.file.Name())
+}
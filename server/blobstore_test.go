@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// commitBlob stores data in b and returns its digest
+func commitBlob(t *testing.T, b *localBlobStore, data string) string {
+	t.Helper()
+
+	w, err := b.Create()
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := w.Write([]byte(data)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	return w.Digest()
+}
+
+func blobExists(t *testing.T, b *localBlobStore, digest string) bool {
+	t.Helper()
+
+	path, err := b.path(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = os.Stat(path)
+	return !errors.Is(err, fs.ErrNotExist)
+}
+
+func TestLocalBlobStoreSharesIdenticalContent(t *testing.T) {
+	b, err := newLocalBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := commitBlob(t, b, "same bytes")
+	second := commitBlob(t, b, "same bytes")
+	if first != second {
+		t.Fatalf("digests differ for identical content: %s, %s", first, second)
+	}
+
+	if err := b.Release(first); err != nil {
+		t.Fatalf("first Release: %v", err)
+	}
+	if !blobExists(t, b, first) {
+		t.Fatal("blob deleted while another upload still refers to it")
+	}
+	if err := b.Release(first); err != nil {
+		t.Fatalf("second Release: %v", err)
+	}
+	if blobExists(t, b, first) {
+		t.Fatal("blob kept after its last reference was released")
+	}
+	if err := b.Release(first); err == nil {
+		t.Fatal("Release with no references held succeeded")
+	}
+}
+
+func TestLocalBlobStoreReclaimAfterRestart(t *testing.T) {
+	root := t.TempDir()
+	b, err := newLocalBlobStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept := commitBlob(t, b, "still uploaded")
+	commitBlob(t, b, "still uploaded")
+	orphan := commitBlob(t, b, "forgotten")
+
+	// A new store over the same directory starts with no reference counts
+	restarted, err := newLocalBlobStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	removed, err := restarted.Reclaim([]string{kept, kept})
+	if err != nil {
+		t.Fatalf("Reclaim: %v", err)
+	}
+	if removed != 1 || blobExists(t, restarted, orphan) {
+		t.Fatalf("Reclaim removed %d objects, want only the unreferenced one", removed)
+	}
+
+	for i := range 2 {
+		if !blobExists(t, restarted, kept) {
+			t.Fatalf("blob deleted after %d of 2 releases", i)
+		}
+		if err := restarted.Release(kept); err != nil {
+			t.Fatalf("Release %d after restart: %v", i+1, err)
+		}
+	}
+	if blobExists(t, restarted, kept) {
+		t.Fatal("blob kept after every upload referring to it was released")
+	}
+}
+
+func TestRestartKeepsIndexedUploads(t *testing.T) {
+	root := t.TempDir()
+	s := newTestServer(t)
+	var err error
+	if s.blobs, err = newLocalBlobStore(root); err != nil {
+		t.Fatal(err)
+	}
+	if s.uploads, err = openUploadIndex(filepath.Join(root, "index")); err != nil {
+		t.Fatal(err)
+	}
+
+	uploadID := initiate(t, s, 10, "text/plain")
+	for i, data := range []string{"hello", "world"} {
+		if err := uploadPart(s, uploadID, int32(i+1), []byte(data)); err != nil {
+			t.Fatalf("UploadPart %d: %v", i+1, err)
+		}
+	}
+	if _, err := complete(s, uploadID, 1, 2); err != nil {
+		t.Fatalf("CompleteUpload: %v", err)
+	}
+	// A part of an upload that is never completed
+	abandoned := initiate(t, s, 5, "text/plain")
+	if err := uploadPart(s, abandoned, 1, []byte("bye!!")); err != nil {
+		t.Fatalf("UploadPart: %v", err)
+	}
+
+	restarted := newTestServer(t)
+	if restarted.blobs, err = newLocalBlobStore(root); err != nil {
+		t.Fatal(err)
+	}
+	if restarted.uploads, err = openUploadIndex(filepath.Join(root, "index")); err != nil {
+		t.Fatal(err)
+	}
+	removed, err := restarted.blobs.Reclaim(restarted.uploads.digests())
+	if err != nil {
+		t.Fatalf("Reclaim: %v", err)
+	}
+	if removed != 1 {
+		t.Fatalf("Reclaim removed %d objects, want only the abandoned part", removed)
+	}
+	if got := readUpload(t, restarted, uploadID); got != "helloworld" {
+		t.Fatalf("upload after restart = %q, want %q", got, "helloworld")
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	userv1 "grpc-go-learning/gen/go/user/v1/user"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// jobRetention is how long a completed job can still be looked up
+	jobRetention = time.Hour
+	// jobGCInterval is how often completed jobs past their retention are dropped
+	jobGCInterval = time.Minute
+)
+
+// notificationJobs tracks asynchronous audience publishes by job ID
+type notificationJobs struct {
+	mu        sync.Mutex
+	retention time.Duration
+	jobs      map[string]*userv1.NotificationJob
+	nextID    int
+}
+
+func newNotificationJobs(retention time.Duration) *notificationJobs {
+	return &notificationJobs{
+		retention: retention,
+		jobs:      make(map[string]*userv1.NotificationJob),
+	}
+}
+
+// start registers a running job for total recipients
+func (j *notificationJobs) start(total int) string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.nextID++
+	jobID := fmt.Sprintf("job_%d", j.nextID)
+	j.jobs[jobID] = &userv1.NotificationJob{
+		JobId:           jobID,
+		State:           userv1.NotificationJobState_NOTIFICATION_JOB_STATE_RUNNING,
+		TotalRecipients: int32(total),
+		CreatedAt:       time.Now().Unix(),
+	}
+	return jobID
+}
+
+// record counts one recipient as delivered, undelivered or deduplicated
+func (j *notificationJobs) record(jobID string, result publishResult) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	job := j.jobs[jobID]
+	switch {
+	case result.deduplicated:
+		job.Deduplicated++
+	case result.delivered > 0:
+		job.Delivered++
+	default:
+		job.Undelivered++
+	}
+}
+
+func (j *notificationJobs) complete(jobID string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	job := j.jobs[jobID]
+	job.State = userv1.NotificationJobState_NOTIFICATION_JOB_STATE_COMPLETED
+	job.CompletedAt = time.Now().Unix()
+}
+
+// collectExpired drops jobs completed longer than the retention ago
+func (j *notificationJobs) collectExpired(now time.Time) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	cutoff := now.Add(-j.retention).Unix()
+	for jobID, job := range j.jobs {
+		if job.State == userv1.NotificationJobState_NOTIFICATION_JOB_STATE_COMPLETED && job.CompletedAt < cutoff {
+			delete(j.jobs, jobID)
+		}
+	}
+}
+
+// runCollector calls collectExpired every interval, forever
+func (j *notificationJobs) runCollector(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		j.collectExpired(now)
+	}
+}
+
+// get returns a copy of the job so callers can read it without the lock
+func (j *notificationJobs) get(jobID string) (*userv1.NotificationJob, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	job, ok := j.jobs[jobID]
+	if !ok {
+		return nil, false
+	}
+	return proto.Clone(job).(*userv1.NotificationJob), true
+}
+
+// matchesAudience reports whether user belongs to audience
+func matchesAudience(user *userv1.User, audience *userv1.NotificationAudience) bool {
+	switch target := audience.Target.(type) {
+	case *userv1.NotificationAudience_AllUsers:
+		return target.AllUsers
+	case *userv1.NotificationAudience_Segment:
+		segment := target.Segment
+		if segment.Status != userv1.UserStatus_USER_STATUS_UNSPECIFIED && user.Status != segment.Status {
+			return false
+		}
+		if user.Age < segment.MinAge {
+			return false
+		}
+		if segment.MaxAge > 0 && user.Age > segment.MaxAge {
+			return false
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// validateAudience checks that audience selects something sensible
+func validateAudience(audience *userv1.NotificationAudience) error {
+	switch target := audience.Target.(type) {
+	case *userv1.NotificationAudience_AllUsers:
+		if !target.AllUsers {
+			return fmt.Errorf("all_users must be true when set")
+		}
+	case *userv1.NotificationAudience_Segment:
+		segment := target.Segment
+		if segment == nil {
+			return fmt.Errorf("segment is required")
+		}
+		if segment.MinAge < 0 || segment.MaxAge < 0 {
+			return fmt.Errorf("age bounds must not be negative")
+		}
+		if segment.MaxAge > 0 && segment.MinAge > segment.MaxAge {
+			return fmt.Errorf("min_age %d is greater than max_age %d", segment.MinAge, segment.MaxAge)
+		}
+	default:
+		return fmt.Errorf("audience target is required")
+	}
+	return nil
+}
+
+// deliverToAudience publishes a copy of template to every recipient, recording progress on jobID
+func (s *server) deliverToAudience(ctx context.Context, jobID string, template *userv1.Notification, recipients []string) {
+	for _, userID := range recipients {
+		notification := proto.Clone(template).(*userv1.Notification)
+		notification.UserId = userID
+		notification.NotificationId = ""
+
+		s.jobs.record(jobID, s.notifications.publish(notification))
+	}
+
+	s.jobs.complete(jobID)
+	slog.InfoContext(ctx, "Notification job finished", "job_id", jobID, "recipients", len(recipients))
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	userv1 "grpc-go-learning/gen/go/user/v1/user"
+)
+
+func TestDeliverToAudienceCountsOnlyReceivedNotifications(t *testing.T) {
+	s := newTestServer(t)
+	s.notifications = newNotificationHub(time.Minute)
+	s.jobs = newNotificationJobs(time.Hour)
+
+	// user_a is listening, user_b isn't, and user_c's only subscriber is full
+	_, unsubscribeA := s.notifications.subscribe("user_a")
+	defer unsubscribeA()
+	full, unsubscribeC := s.notifications.subscribe("user_c")
+	defer unsubscribeC()
+	for range cap(full) {
+		s.notifications.publish(&userv1.Notification{UserId: "user_c"})
+	}
+	// user_d already got this dedup key
+	s.notifications.publish(&userv1.Notification{UserId: "user_d", DedupKey: "promo"})
+
+	recipients := []string{"user_a", "user_b", "user_c", "user_d"}
+	jobID := s.jobs.start(len(recipients))
+	s.deliverToAudience(t.Context(), jobID, &userv1.Notification{Message: "hi", DedupKey: "promo"}, recipients)
+
+	job, ok := s.jobs.get(jobID)
+	if !ok {
+		t.Fatal("job not found")
+	}
+	if job.Delivered != 1 || job.Undelivered != 2 || job.Deduplicated != 1 {
+		t.Fatalf("delivered %d, undelivered %d, deduplicated %d; want 1, 2, 1",
+			job.Delivered, job.Undelivered, job.Deduplicated)
+	}
+	if job.State != userv1.NotificationJobState_NOTIFICATION_JOB_STATE_COMPLETED {
+		t.Fatalf("job state = %v, want completed", job.State)
+	}
+}
+
+func TestNotificationJobsCollectExpired(t *testing.T) {
+	j := newNotificationJobs(time.Hour)
+	running := j.start(1)
+	recent := j.start(1)
+	j.complete(recent)
+	old := j.start(1)
+	j.complete(old)
+	j.jobs[old].CompletedAt = time.Now().Add(-2 * time.Hour).Unix()
+
+	j.collectExpired(time.Now())
+
+	for jobID, want := range map[string]bool{running: true, recent: true, old: false} {
+		if _, ok := j.get(jobID); ok != want {
+			t.Errorf("job %s kept = %v, want %v", jobID, ok, want)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"strings"
+)
+
+// crc32cTable is the Castagnoli table used for per-chunk checksums
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// verifyChunkCRC checks data against the checksum sent with a chunk, if any
+func verifyChunkCRC(data []byte, expected *uint32) error {
+	if expected == nil {
+		return nil
+	}
+	if actual := crc32.Checksum(data, crc32cTable); actual != *expected {
+		return fmt.Errorf("crc32c mismatch: expected %08x, computed %08x", *expected, actual)
+	}
+	return nil
+}
+
+// normalizeSHA256 validates a hex-encoded SHA-256 digest and lower-cases it
+func normalizeSHA256(digest string) (string, error) {
+	digest = strings.ToLower(digest)
+	if b, err := hex.DecodeString(digest); err != nil || len(b) != 32 {
+		return "", fmt.Errorf("sha256 must be 64 hex characters, got %q", digest)
+	}
+	return digest, nil
+}
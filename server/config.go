@@ -0,0 +1,441 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configEnvPrefix prefixes the environment variables that override the config file
+const configEnvPrefix = "USER_SERVER_"
+
+// serverConfig is everything the server can be configured with.
+// Values are applied in order: defaults, config file, environment, flags.
+type serverConfig struct {
+	ListenAddr      string            `json:"listen_addr" yaml:"listen_addr"`
+	LogLevel        string            `json:"log_level" yaml:"log_level"`
+	LogFormat       string            `json:"log_format" yaml:"log_format"`
+	LogMethods      map[string]string `json:"log_methods" yaml:"log_methods"` // method or "/service/*" -> level or "off"
+	ShutdownTimeout duration          `json:"shutdown_timeout" yaml:"shutdown_timeout"`
+	DrainDelay      duration          `json:"shutdown_drain_delay" yaml:"shutdown_drain_delay"`
+	Reflection      bool              `json:"reflection" yaml:"reflection"`     // expose the API for grpcurl; keep off in production
+	MetricsAddr     string            `json:"metrics_addr" yaml:"metrics_addr"` // HTTP address of /metrics; empty disables it
+	TLS             tlsConfig         `json:"tls" yaml:"tls"`
+	Tracing         tracingConfig     `json:"tracing" yaml:"tracing"`
+	Limits          limitsConfig      `json:"limits" yaml:"limits"`
+	Storage         storageConfig     `json:"storage" yaml:"storage"`
+}
+
+// tlsConfig enables TLS when both files are set, and mutual TLS when a client CA is also set
+type tlsConfig struct {
+	CertFile       string   `json:"cert_file" yaml:"cert_file"`
+	KeyFile        string   `json:"key_file" yaml:"key_file"`
+	ClientCAFile   string   `json:"client_ca_file" yaml:"client_ca_file"`
+	ClientAuth     string   `json:"client_auth" yaml:"client_auth"`           // require or optional; only with client_ca_file
+	ReloadInterval duration `json:"reload_interval" yaml:"reload_interval"`   // how often changed files are picked up; 0 never
+	CallerFromCert bool     `json:"caller_from_cert" yaml:"caller_from_cert"` // client certificate common names are user IDs
+}
+
+// tracingConfig selects where finished spans go; traceparent is propagated either way
+type tracingConfig struct {
+	Exporter string `json:"exporter" yaml:"exporter"` // none, stdout or file
+	File     string `json:"file" yaml:"file"`
+}
+
+type limitsConfig struct {
+	MaxUploadSize       int64    `json:"max_upload_size" yaml:"max_upload_size"`
+	UserQuota           int64    `json:"user_quota" yaml:"user_quota"`
+	IncompleteUploadTTL duration `json:"incomplete_upload_ttl" yaml:"incomplete_upload_ttl"`
+	DedupWindow         duration `json:"dedup_window" yaml:"dedup_window"`
+	AllowedContentTypes []string `json:"allowed_content_types" yaml:"allowed_content_types"`
+}
+
+type storageConfig struct {
+	Backend string `json:"backend" yaml:"backend"` // only "local" for now
+	Dir     string `json:"dir" yaml:"dir"`
+}
+
+// duration reads as a Go duration string such as "90s" or "1h" in config files
+type duration time.Duration
+
+func (d *duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = duration(parsed)
+	return nil
+}
+
+func (d duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// defaultConfig returns the settings used when nothing overrides them
+func defaultConfig() *serverConfig {
+	return &serverConfig{
+		ListenAddr:      ":50051",
+		LogLevel:        "info",
+		LogFormat:       "text",
+		MetricsAddr:     "127.0.0.1:9090",
+		ShutdownTimeout: duration(defaultShutdownTimeout),
+		DrainDelay:      duration(defaultDrainDelay),
+		TLS: tlsConfig{
+			ClientAuth:     clientAuthRequire,
+			ReloadInterval: duration(defaultTLSReloadInterval),
+		},
+		Tracing: tracingConfig{Exporter: "none"},
+		Limits: limitsConfig{
+			MaxUploadSize:       defaultMaxUploadSize,
+			UserQuota:           defaultUserQuota,
+			IncompleteUploadTTL: duration(defaultIncompleteUploadTTL),
+			DedupWindow:         duration(defaultDedupWindow),
+			AllowedContentTypes: append([]string(nil), defaultAllowedContentTypes...),
+		},
+		Storage: storageConfig{
+			Backend: "local",
+			Dir:     defaultUploadDir,
+		},
+	}
+}
+
+// configSetting is a value that can be overridden by an environment variable and a flag
+type configSetting struct {
+	flag  string
+	env   string // without configEnvPrefix
+	usage string
+	set   func(c *serverConfig, value string) error
+}
+
+var configSettings = []configSetting{
+	{"listen", "LISTEN_ADDR", "address to listen on", func(c *serverConfig, v string) error {
+		c.ListenAddr = v
+		return nil
+	}},
+	{"log-level", "LOG_LEVEL", "debug, info, warn or error", func(c *serverConfig, v string) error {
+		c.LogLevel = v
+		return nil
+	}},
+	{"log-format", "LOG_FORMAT", "text or json", func(c *serverConfig, v string) error {
+		c.LogFormat = v
+		return nil
+	}},
+	{"log-methods", "LOG_METHODS", "per-method RPC log levels as method=level,...; level may be off", func(c *serverConfig, v string) (err error) {
+		c.LogMethods, err = parseMethodLevels(v)
+		return err
+	}},
+	{"shutdown-timeout", "SHUTDOWN_TIMEOUT", "how long in-flight RPCs may drain at shutdown", func(c *serverConfig, v string) error {
+		return c.ShutdownTimeout.UnmarshalText([]byte(v))
+	}},
+	{"shutdown-drain-delay", "SHUTDOWN_DRAIN_DELAY", "how long to keep serving after health reports NOT_SERVING at shutdown", func(c *serverConfig, v string) error {
+		return c.DrainDelay.UnmarshalText([]byte(v))
+	}},
+	{"metrics-addr", "METRICS_ADDR", "HTTP address serving /metrics; empty disables it", func(c *serverConfig, v string) error {
+		c.MetricsAddr = v
+		return nil
+	}},
+	{"reflection", "REFLECTION", "serve gRPC reflection: true or false", func(c *serverConfig, v string) error {
+		return parseBool(v, &c.Reflection)
+	}},
+	{"tls-cert", "TLS_CERT_FILE", "TLS certificate file", func(c *serverConfig, v string) error {
+		c.TLS.CertFile = v
+		return nil
+	}},
+	{"tls-key", "TLS_KEY_FILE", "TLS private key file", func(c *serverConfig, v string) error {
+		c.TLS.KeyFile = v
+		return nil
+	}},
+	{"tls-client-ca", "TLS_CLIENT_CA_FILE", "CA bundle that client certificates must chain to; enables mutual TLS", func(c *serverConfig, v string) error {
+		c.TLS.ClientCAFile = v
+		return nil
+	}},
+	{"tls-client-auth", "TLS_CLIENT_AUTH", "with a client CA, require or optional client certificates", func(c *serverConfig, v string) error {
+		c.TLS.ClientAuth = v
+		return nil
+	}},
+	{"tls-reload-interval", "TLS_RELOAD_INTERVAL", "how often changed TLS files are reloaded; 0 disables reloading", func(c *serverConfig, v string) error {
+		return c.TLS.ReloadInterval.UnmarshalText([]byte(v))
+	}},
+	{"tls-caller-from-cert", "TLS_CALLER_FROM_CERT", "treat client certificate common names as user IDs: true or false", func(c *serverConfig, v string) error {
+		return parseBool(v, &c.TLS.CallerFromCert)
+	}},
+	{"trace-exporter", "TRACE_EXPORTER", "where to export spans: none, stdout or file", func(c *serverConfig, v string) error {
+		c.Tracing.Exporter = v
+		return nil
+	}},
+	{"trace-file", "TRACE_FILE", "file spans are appended to with -trace-exporter=file", func(c *serverConfig, v string) error {
+		c.Tracing.File = v
+		return nil
+	}},
+	{"max-upload-size", "MAX_UPLOAD_SIZE", "largest accepted upload in bytes", func(c *serverConfig, v string) error {
+		return parseInt64(v, &c.Limits.MaxUploadSize)
+	}},
+	{"user-quota", "USER_QUOTA", "upload storage per user in bytes", func(c *serverConfig, v string) error {
+		return parseInt64(v, &c.Limits.UserQuota)
+	}},
+	{"incomplete-upload-ttl", "INCOMPLETE_UPLOAD_TTL", "how long an interrupted upload can be resumed", func(c *serverConfig, v string) error {
+		return c.Limits.IncompleteUploadTTL.UnmarshalText([]byte(v))
+	}},
+	{"dedup-window", "DEDUP_WINDOW", "how long a notification dedup_key is remembered", func(c *serverConfig, v string) error {
+		return c.Limits.DedupWindow.UnmarshalText([]byte(v))
+	}},
+	{"allowed-content-types", "ALLOWED_CONTENT_TYPES", "comma-separated upload media types; type/* matches a family", func(c *serverConfig, v string) error {
+		c.Limits.AllowedContentTypes = splitList(v)
+		return nil
+	}},
+	{"storage-backend", "STORAGE_BACKEND", "where uploads are stored: local", func(c *serverConfig, v string) error {
+		c.Storage.Backend = v
+		return nil
+	}},
+	{"storage-dir", "STORAGE_DIR", "directory of the local storage backend", func(c *serverConfig, v string) error {
+		c.Storage.Dir = v
+		return nil
+	}},
+}
+
+// loadConfig builds the configuration from defaults, the config file, the environment and args
+func loadConfig(args []string) (*serverConfig, error) {
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	configPath := fs.String("config", os.Getenv(configEnvPrefix+"CONFIG"), "YAML or JSON config file")
+
+	// Flags are applied last, so only record them while parsing
+	flagValues := make(map[string]string)
+	for _, setting := range configSettings {
+		name := setting.flag
+		fs.Func(name, setting.usage+" (env "+configEnvPrefix+setting.env+")", func(v string) error {
+			flagValues[name] = v
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	config := defaultConfig()
+	if *configPath != "" {
+		if err := config.readFile(*configPath); err != nil {
+			return nil, fmt.Errorf("config file %s: %w", *configPath, err)
+		}
+	}
+
+	for _, setting := range configSettings {
+		if v, ok := os.LookupEnv(configEnvPrefix + setting.env); ok {
+			if err := setting.set(config, v); err != nil {
+				return nil, fmt.Errorf("%s%s: %w", configEnvPrefix, setting.env, err)
+			}
+		}
+	}
+	for _, setting := range configSettings {
+		if v, ok := flagValues[setting.flag]; ok {
+			if err := setting.set(config, v); err != nil {
+				return nil, fmt.Errorf("-%s: %w", setting.flag, err)
+			}
+		}
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return config, nil
+}
+
+// readFile overlays the settings present in a YAML or JSON file; unknown keys are errors
+func (c *serverConfig) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(c)
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(c)
+		if errors.Is(err, io.EOF) {
+			err = nil // empty file
+		}
+	default:
+		return fmt.Errorf("unsupported extension %q, use .yaml, .yml or .json", filepath.Ext(path))
+	}
+	return err
+}
+
+// validate reports every invalid setting at once
+func (c *serverConfig) validate() error {
+	var errs []error
+
+	if _, _, err := net.SplitHostPort(c.ListenAddr); err != nil {
+		errs = append(errs, fmt.Errorf("listen_addr %q: %w", c.ListenAddr, err))
+	}
+	if c.MetricsAddr != "" {
+		if _, _, err := net.SplitHostPort(c.MetricsAddr); err != nil {
+			errs = append(errs, fmt.Errorf("metrics_addr %q: %w", c.MetricsAddr, err))
+		}
+	}
+	if _, err := parseLogLevel(c.LogLevel); err != nil {
+		errs = append(errs, fmt.Errorf("log_level: %w", err))
+	}
+	if c.LogFormat != "text" && c.LogFormat != "json" {
+		errs = append(errs, fmt.Errorf("log_format %q: use text or json", c.LogFormat))
+	}
+	for method, level := range c.LogMethods {
+		if !strings.HasPrefix(method, "/") || !strings.Contains(method[1:], "/") {
+			errs = append(errs, fmt.Errorf("log_methods: %q is not a /package.Service/Method or /package.Service/* name", method))
+		}
+		if level == logLevelOff {
+			continue
+		}
+		if _, err := parseLogLevel(level); err != nil {
+			errs = append(errs, fmt.Errorf("log_methods[%s]: %w", method, err))
+		}
+	}
+
+	if c.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("shutdown_timeout must be positive"))
+	}
+	if c.DrainDelay < 0 {
+		errs = append(errs, errors.New("shutdown_drain_delay must not be negative"))
+	}
+
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		errs = append(errs, errors.New("tls.cert_file and tls.key_file must be set together"))
+	}
+	if c.TLS.ClientCAFile != "" && c.TLS.CertFile == "" {
+		errs = append(errs, errors.New("tls.client_ca_file requires tls.cert_file and tls.key_file"))
+	}
+	if _, ok := clientAuthTypes[c.TLS.ClientAuth]; !ok {
+		errs = append(errs, fmt.Errorf("tls.client_auth %q: use require or optional", c.TLS.ClientAuth))
+	}
+	if c.TLS.CallerFromCert && c.TLS.ClientCAFile == "" {
+		errs = append(errs, errors.New("tls.caller_from_cert requires tls.client_ca_file"))
+	}
+	if c.TLS.ReloadInterval < 0 {
+		errs = append(errs, errors.New("tls.reload_interval must not be negative"))
+	}
+	for _, file := range []string{c.TLS.CertFile, c.TLS.KeyFile, c.TLS.ClientCAFile} {
+		if file == "" {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			errs = append(errs, fmt.Errorf("tls: %w", err))
+		}
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout":
+	case "file":
+		if c.Tracing.File == "" {
+			errs = append(errs, errors.New("tracing.file is required with the file exporter"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter %q: use none, stdout or file", c.Tracing.Exporter))
+	}
+
+	if c.Limits.MaxUploadSize <= 0 {
+		errs = append(errs, fmt.Errorf("limits.max_upload_size must be positive, got %d", c.Limits.MaxUploadSize))
+	}
+	if c.Limits.UserQuota < c.Limits.MaxUploadSize {
+		errs = append(errs, fmt.Errorf("limits.user_quota %d is smaller than limits.max_upload_size %d",
+			c.Limits.UserQuota, c.Limits.MaxUploadSize))
+	}
+	if c.Limits.IncompleteUploadTTL <= 0 {
+		errs = append(errs, errors.New("limits.incomplete_upload_ttl must be positive"))
+	}
+	if c.Limits.DedupWindow < 0 {
+		errs = append(errs, errors.New("limits.dedup_window must not be negative"))
+	}
+	if len(c.Limits.AllowedContentTypes) == 0 {
+		errs = append(errs, errors.New("limits.allowed_content_types must not be empty"))
+	}
+	for i, contentType := range c.Limits.AllowedContentTypes {
+		family, isFamily := strings.CutSuffix(contentType, "/*")
+		if isFamily && family != "" && !strings.Contains(family, "/") {
+			continue
+		}
+		normalized, err := normalizeContentType(contentType)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("limits.allowed_content_types: %w", err))
+			continue
+		}
+		c.Limits.AllowedContentTypes[i] = normalized
+	}
+
+	switch c.Storage.Backend {
+	case "local":
+		if c.Storage.Dir == "" {
+			errs = append(errs, errors.New("storage.dir is required for the local backend"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("storage.backend %q is not supported, use local", c.Storage.Backend))
+	}
+
+	return errors.Join(errs...)
+}
+
+// openBlobStore creates the configured storage backend
+func (c *serverConfig) openBlobStore() (blobStore, error) {
+	// validate only lets supported backends through
+	return newLocalBlobStore(c.Storage.Dir)
+}
+
+// openUploadIndex loads the upload index kept beside the stored data
+func (c *serverConfig) openUploadIndex() (*uploadIndex, error) {
+	return openUploadIndex(filepath.Join(c.Storage.Dir, "index"))
+}
+
+// parseLogLevel maps a log_level setting to a slog level
+func parseLogLevel(level string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return 0, fmt.Errorf("unknown level %q, use debug, info, warn or error", level)
+	}
+	return l, nil
+}
+
+func parseInt64(value string, dst *int64) error {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return err
+	}
+	*dst = n
+	return nil
+}
+
+func parseBool(value string, dst *bool) error {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	*dst = b
+	return nil
+}
+
+// splitList splits a comma-separated setting, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
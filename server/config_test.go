@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfig writes content to a config file named name and returns its path
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	for _, file := range []struct{ name, content string }{
+		{"server.yaml", "listen_addr: \":1001\"\nlog_level: debug\nlog_format: json\nshutdown_timeout: 5s\n"},
+		{"server.json", `{"listen_addr": ":1001", "log_level": "debug", "log_format": "json", "shutdown_timeout": "5s"}`},
+	} {
+		t.Run(file.name, func(t *testing.T) {
+			path := writeConfig(t, file.name, file.content)
+			t.Setenv(configEnvPrefix+"LISTEN_ADDR", ":1002")
+			t.Setenv(configEnvPrefix+"LOG_LEVEL", "warn")
+
+			config, err := loadConfig([]string{"-config", path, "-listen", ":1003"})
+			if err != nil {
+				t.Fatalf("loadConfig: %v", err)
+			}
+
+			checks := []struct {
+				setting, got, want string
+			}{
+				{"listen_addr (file, env and flag)", config.ListenAddr, ":1003"},
+				{"log_level (file and env)", config.LogLevel, "warn"},
+				{"log_format (file)", config.LogFormat, "json"},
+				{"shutdown_timeout (file)", time.Duration(config.ShutdownTimeout).String(), "5s"},
+				{"metrics_addr (default)", config.MetricsAddr, "127.0.0.1:9090"},
+			}
+			for _, c := range checks {
+				if c.got != c.want {
+					t.Errorf("%s = %q, want %q", c.setting, c.got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadConfigRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string // YAML content; empty for no config file
+		env     map[string]string
+		args    []string
+		wantErr []string // substrings of the error
+	}{
+		{name: "unknown key", file: "listen_adr: \":50051\"\n", wantErr: []string{"listen_adr"}},
+		{name: "zero shutdown_timeout", file: "shutdown_timeout: 0s\n", wantErr: []string{"shutdown_timeout must be positive"}},
+		{name: "bad listen_addr flag", args: []string{"-listen", "50051"}, wantErr: []string{"listen_addr \"50051\""}},
+		{name: "caller_from_cert without client CA", args: []string{"-tls-caller-from-cert", "true"}, wantErr: []string{"tls.caller_from_cert requires tls.client_ca_file"}},
+		{name: "bad duration in env", env: map[string]string{"SHUTDOWN_TIMEOUT": "soon"}, wantErr: []string{configEnvPrefix + "SHUTDOWN_TIMEOUT"}},
+		{
+			name:    "every invalid setting reported",
+			file:    "listen_addr: nowhere\nlog_format: xml\n",
+			args:    []string{"-shutdown-timeout", "0s"},
+			wantErr: []string{"listen_addr \"nowhere\"", "log_format \"xml\"", "shutdown_timeout must be positive"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(configEnvPrefix+key, value)
+			}
+			args := tt.args
+			if tt.file != "" {
+				args = append([]string{"-config", writeConfig(t, "server.yaml", tt.file)}, args...)
+			}
+
+			_, err := loadConfig(args)
+			if err == nil {
+				t.Fatal("loadConfig succeeded")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExampleConfigLoads(t *testing.T) {
+	if _, err := loadConfig([]string{"-config", "config.example.yaml"}); err != nil {
+		t.Fatalf("config.example.yaml: %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"unicode"
+
+	"google.golang.org/grpc/codes"
+)
+
+// maxFilenameLength is the longest filename kept after sanitizing
+const maxFilenameLength = 255
+
+// sniffLength is how many leading bytes http.DetectContentType considers
+const sniffLength = 512
+
+// defaultAllowedContentTypes are the media types accepted for uploads; "type/*" matches a whole family
+var defaultAllowedContentTypes = []string{
+	"application/json",
+	"application/octet-stream",
+	"application/pdf",
+	"text/csv",
+	"text/plain",
+	"image/*",
+}
+
+// signatureContentTypes are declared types the sniffer recognizes by their magic bytes,
+// so a generic sniff result means the content isn't really of that type
+var signatureContentTypes = map[string]bool{
+	"application/pdf":    true,
+	"application/zip":    true,
+	"application/x-gzip": true,
+	"application/ogg":    true,
+	"application/wasm":   true,
+}
+
+// sanitizeFilename reduces a client-supplied filename to a safe base name,
+// dropping directories, control characters and path separators
+func sanitizeFilename(name string) (string, error) {
+	name = strings.ReplaceAll(name, `\`, "/")
+	name = path.Base(path.Clean("/" + name))
+
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '/' || r == ':' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	if name == "" || name == "." || name == ".." || name == "/" {
+		return "", fmt.Errorf("filename has no usable characters")
+	}
+	if len(name) > maxFilenameLength {
+		ext := path.Ext(name)
+		if len(ext) > 16 {
+			ext = ""
+		}
+		name = strings.ToValidUTF8(name[:maxFilenameLength-len(ext)], "") + ext
+	}
+	return name, nil
+}
+
+// normalizeContentType returns the lower-cased media type without parameters
+func normalizeContentType(contentType string) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("invalid content_type %q: %w", contentType, err)
+	}
+	return mediaType, nil
+}
+
+// contentTypeAllowed reports whether mediaType matches an entry of allowList
+func contentTypeAllowed(mediaType string, allowList []string) bool {
+	for _, allowed := range allowList {
+		if allowed == mediaType {
+			return true
+		}
+		if family, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(mediaType, family+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// sniffContentType detects the media type of the start of an upload
+func sniffContentType(data []byte) string {
+	mediaType, err := normalizeContentType(http.DetectContentType(data))
+	if err != nil {
+		return "application/octet-stream"
+	}
+	return mediaType
+}
+
+// isTextual reports whether mediaType holds text the sniffer reports as text/*
+func isTextual(mediaType string) bool {
+	if strings.HasPrefix(mediaType, "text/") {
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/xml", "application/javascript", "application/x-yaml", "application/yaml":
+		return true
+	}
+	return strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml")
+}
+
+// hasSignature reports whether the sniffer can positively identify mediaType
+func hasSignature(mediaType string) bool {
+	for _, family := range []string{"image/", "audio/", "video/", "font/"} {
+		if strings.HasPrefix(mediaType, family) {
+			return true
+		}
+	}
+	return signatureContentTypes[mediaType]
+}
+
+// contentTypeMatches reports whether sniffed content is consistent with the declared media type.
+// The sniffer only knows a fixed set of signatures, so generic results are accepted
+// unless the declared type is one it would have recognized.
+func contentTypeMatches(declared, sniffed string) bool {
+	if declared == sniffed || declared == "application/octet-stream" {
+		return true
+	}
+	if isTextual(declared) {
+		return strings.HasPrefix(sniffed, "text/")
+	}
+	if sniffed == "application/octet-stream" || sniffed == "text/plain" {
+		return !hasSignature(declared)
+	}
+	return false
+}
+
+// refineByExtension narrows a generic text/plain sniff result using the filename,
+// since the sniffer can't tell JSON from other text
+func refineByExtension(sniffed, filename string) string {
+	if sniffed == "text/plain" && strings.EqualFold(path.Ext(filename), ".json") {
+		return "application/json"
+	}
+	return sniffed
+}
+
+// checkContent sniffs the first bytes of an upload and returns the media type to record.
+// An empty declared type is replaced by the sniffed one, refined by the filename's
+// extension, which must then be allowed itself.
+func (s *server) checkContent(declared, filename string, firstChunk []byte) (string, error) {
+	sniffed := sniffContentType(firstChunk)
+
+	if declared == "" {
+		sniffed = refineByExtension(sniffed, filename)
+		if !contentTypeAllowed(sniffed, s.allowedContentTypes) {
+			return "", uploadViolation(codes.InvalidArgument, "chunk.data",
+				fmt.Sprintf("detected content type %s is not allowed", sniffed))
+		}
+		return sniffed, nil
+	}
+
+	if !contentTypeMatches(declared, sniffed) {
+		return "", uploadViolation(codes.InvalidArgument, "metadata.content_type",
+			fmt.Sprintf("content looks like %s, not the declared %s", sniffed, declared))
+	}
+	return declared, nil
+}
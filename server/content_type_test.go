@@ -0,0 +1,122 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain", in: "report.pdf", want: "report.pdf"},
+		{name: "unix traversal", in: "../../etc/passwd", want: "passwd"},
+		{name: "windows path", in: `C:\Users\me\notes.txt`, want: "notes.txt"},
+		{name: "absolute path", in: "/var/data/file.csv", want: "file.csv"},
+		{name: "control characters", in: "bad\x00na\nme.txt", want: "badname.txt"},
+		{name: "surrounding spaces", in: "  spaced.txt  ", want: "spaced.txt"},
+		{name: "drive colon", in: "c:file.txt", want: "cfile.txt"},
+		{name: "only dots", in: "..", wantErr: true},
+		{name: "only separators", in: "///", wantErr: true},
+		{name: "empty after cleaning", in: "\x01\x02", wantErr: true},
+		{name: "too long keeps extension", in: strings.Repeat("a", 300) + ".json", want: strings.Repeat("a", maxFilenameLength-5) + ".json"},
+		{name: "too long with long extension", in: strings.Repeat("b", 300) + "." + strings.Repeat("c", 20), want: strings.Repeat("b", maxFilenameLength)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitizeFilename(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("sanitizeFilename(%q) = %q, want an error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sanitizeFilename(%q): %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Fatalf("sanitizeFilename(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentTypeMatches(t *testing.T) {
+	tests := []struct {
+		declared, sniffed string
+		want              bool
+	}{
+		{"application/pdf", "application/pdf", true},
+		{"application/octet-stream", "application/pdf", true},
+		{"text/plain", "text/plain", true},
+		{"text/csv", "text/plain", true},
+		{"application/json", "text/plain", true},
+		{"text/plain", "application/pdf", false},
+		{"application/json", "application/x-gzip", false},
+		{"application/pdf", "text/plain", false},
+		{"application/pdf", "application/octet-stream", false},
+		{"image/png", "application/octet-stream", false},
+		{"application/x-custom", "application/octet-stream", true},
+		{"application/x-custom", "text/plain", true},
+		{"image/png", "image/jpeg", false},
+	}
+
+	for _, tt := range tests {
+		if got := contentTypeMatches(tt.declared, tt.sniffed); got != tt.want {
+			t.Errorf("contentTypeMatches(%q, %q) = %v, want %v", tt.declared, tt.sniffed, got, tt.want)
+		}
+	}
+}
+
+func TestContentTypeAllowed(t *testing.T) {
+	allow := []string{"application/json", "image/*"}
+	tests := []struct {
+		mediaType string
+		want      bool
+	}{
+		{"application/json", true},
+		{"image/png", true},
+		{"image", false},
+		{"imagex/png", false},
+		{"application/x-gzip", false},
+	}
+
+	for _, tt := range tests {
+		if got := contentTypeAllowed(tt.mediaType, allow); got != tt.want {
+			t.Errorf("contentTypeAllowed(%q) = %v, want %v", tt.mediaType, got, tt.want)
+		}
+	}
+}
+
+func TestCheckContentUndeclared(t *testing.T) {
+	s := &server{allowedContentTypes: defaultAllowedContentTypes}
+	tests := []struct {
+		filename string
+		data     string
+		want     string
+		wantErr  bool
+	}{
+		{filename: "user_data.json", data: `{"name":"Ruffy"}`, want: "application/json"},
+		{filename: "USER.JSON", data: `{"name":"Ruffy"}`, want: "application/json"},
+		{filename: "notes.txt", data: `{"name":"Ruffy"}`, want: "text/plain"},
+		{filename: "fake.json", data: "%PDF-1.7\n", want: "application/pdf"},
+		{filename: "archive.json", data: "\x1f\x8b\x08\x00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := s.checkContent("", tt.filename, []byte(tt.data))
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("checkContent(%q) = %q, want an error", tt.filename, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("checkContent(%q) = %q, %v, want %q", tt.filename, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"log/slog"
+
+	userv1 "grpc-go-learning/gen/go/user/v1/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultDownloadChunkSize is used when the request doesn't ask for a chunk size
+	defaultDownloadChunkSize = 64 * 1024
+	// maxDownloadChunkSize caps requested chunk sizes below gRPC's default 4MB message limit
+	maxDownloadChunkSize = 1024 * 1024
+)
+
+// DownloadUserData implements server-side streaming of a stored upload
+func (s *server) DownloadUserData(req *userv1.DownloadUserDataRequest, stream userv1.UserService_DownloadUserDataServer) error {
+	// Validate request
+	if req.UploadId == "" {
+		return status.Error(codes.InvalidArgument, "upload_id is required")
+	}
+	if req.UserId == "" {
+		return status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.Offset < 0 || req.Length < 0 {
+		return status.Error(codes.InvalidArgument, "offset and length must not be negative")
+	}
+	if req.ChunkSize < 0 || req.ChunkSize > maxDownloadChunkSize {
+		return status.Errorf(codes.InvalidArgument, "chunk_size must be between 0 and %d", maxDownloadChunkSize)
+	}
+
+	record, exists := s.uploads.get(req.UploadId)
+	if !exists {
+		return status.Errorf(codes.NotFound, "upload %s not found", req.UploadId)
+	}
+	if record.userID != req.UserId {
+		return status.Errorf(codes.PermissionDenied, "upload %s belongs to another user", req.UploadId)
+	}
+	if req.Offset > record.size {
+		return status.Errorf(codes.OutOfRange, "offset %d is beyond the end of upload %s (%d bytes)", req.Offset, req.UploadId, record.size)
+	}
+
+	blob, err := s.blobs.Open(record.sha256)
+	if errors.Is(err, fs.ErrNotExist) {
+		return status.Errorf(codes.NotFound, "upload %s not found", req.UploadId)
+	}
+	if err != nil {
+		slog.ErrorContext(stream.Context(), "Failed to open upload", "upload_id", req.UploadId, "error", err)
+		return status.Errorf(codes.Internal, "failed to open upload: %v", err)
+	}
+	defer blob.Close()
+
+	if _, err := blob.Seek(req.Offset, io.SeekStart); err != nil {
+		return status.Errorf(codes.Internal, "failed to seek upload: %v", err)
+	}
+
+	remaining := record.size - req.Offset
+	if req.Length > 0 && req.Length < remaining {
+		remaining = req.Length
+	}
+
+	chunkSize := int64(defaultDownloadChunkSize)
+	if req.ChunkSize > 0 {
+		chunkSize = int64(req.ChunkSize)
+	}
+
+	var chunkNumber int32
+	for remaining > 0 {
+		// Check if client has disconnected
+		if stream.Context().Err() != nil {
+			return status.FromContextError(stream.Context().Err()).Err()
+		}
+
+		// A sent message may still be referenced by interceptors, so each chunk gets its own buffer
+		n := min(chunkSize, remaining)
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(blob, buf); err != nil {
+			slog.ErrorContext(stream.Context(), "Failed to read upload", "upload_id", req.UploadId, "error", err)
+			return status.Errorf(codes.Internal, "failed to read upload: %v", err)
+		}
+
+		crc := crc32.Checksum(buf, crc32cTable)
+		if err := stream.Send(&userv1.UserDataChunk{
+			Data:        buf,
+			ChunkNumber: chunkNumber,
+			Crc32C:      &crc,
+		}); err != nil {
+			return status.Errorf(codes.Internal, "failed to send chunk: %v", err)
+		}
+
+		remaining -= n
+		chunkNumber++
+	}
+
+	slog.InfoContext(stream.Context(), "Finished streaming upload", "upload_id", req.UploadId, "chunks", chunkNumber)
+	return nil
+}
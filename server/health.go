@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	userv1 "grpc-go-learning/gen/go/user/v1/user"
+
+	"google.golang.org/grpc/codes"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// storageCheckInterval is how often the storage backend is probed for the health service
+const storageCheckInterval = 10 * time.Second
+
+// servingStatus is the health of one service
+type servingStatus = healthgrpc.HealthCheckResponse_ServingStatus
+
+// healthService implements grpc.health.v1.Health. Unlike the stock health server it
+// ends Watch streams at shutdown, so they don't hold up GracefulStop.
+type healthService struct {
+	healthgrpc.UnimplementedHealthServer
+
+	mu       sync.Mutex
+	statuses map[string]servingStatus                   // service name, "" for the whole server -> status
+	watchers map[string]map[chan servingStatus]struct{} // service name -> Watch channels
+	closed   bool                                       // set at shutdown; statuses are frozen
+}
+
+// newHealthService reports the server and each of services as SERVING
+func newHealthService(services ...string) *healthService {
+	h := &healthService{
+		statuses: map[string]servingStatus{"": healthgrpc.HealthCheckResponse_SERVING},
+		watchers: make(map[string]map[chan servingStatus]struct{}),
+	}
+	for _, service := range services {
+		h.statuses[service] = healthgrpc.HealthCheckResponse_SERVING
+	}
+	return h
+}
+
+// setStatus changes the status of service and notifies its watchers; ignored after shutdown
+func (h *healthService) setStatus(service string, st servingStatus) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return
+	}
+	h.setStatusLocked(service, st)
+}
+
+// setStatusLocked records st and hands it to each watcher, replacing any status it hasn't sent yet; h.mu must be held
+func (h *healthService) setStatusLocked(service string, st servingStatus) {
+	h.statuses[service] = st
+	for ch := range h.watchers[service] {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- st
+	}
+}
+
+// shutdown reports every service as NOT_SERVING and ends all Watch streams
+func (h *healthService) shutdown() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for service := range h.statuses {
+		h.setStatusLocked(service, healthgrpc.HealthCheckResponse_NOT_SERVING)
+	}
+	h.closed = true
+	for service, watchers := range h.watchers {
+		for ch := range watchers {
+			close(ch)
+		}
+		delete(h.watchers, service)
+	}
+}
+
+// Check reports the current status of a service
+func (h *healthService) Check(ctx context.Context, req *healthgrpc.HealthCheckRequest) (*healthgrpc.HealthCheckResponse, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	st, ok := h.statuses[req.Service]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.Service)
+	}
+	return &healthgrpc.HealthCheckResponse{Status: st}, nil
+}
+
+// List reports the status of every service
+func (h *healthService) List(ctx context.Context, req *healthgrpc.HealthListRequest) (*healthgrpc.HealthListResponse, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	resp := &healthgrpc.HealthListResponse{Statuses: make(map[string]*healthgrpc.HealthCheckResponse, len(h.statuses))}
+	for service, st := range h.statuses {
+		resp.Statuses[service] = &healthgrpc.HealthCheckResponse{Status: st}
+	}
+	return resp, nil
+}
+
+// Watch streams the status of a service, starting with the current one and then every change
+func (h *healthService) Watch(req *healthgrpc.HealthCheckRequest, stream healthgrpc.Health_WatchServer) error {
+	// Buffer one so a status can be handed over without waiting for the stream
+	ch := make(chan servingStatus, 1)
+
+	h.mu.Lock()
+	current, ok := h.statuses[req.Service]
+	if !ok {
+		current = healthgrpc.HealthCheckResponse_SERVICE_UNKNOWN
+	}
+	ch <- current
+	if h.closed {
+		close(ch)
+	} else {
+		if h.watchers[req.Service] == nil {
+			h.watchers[req.Service] = make(map[chan servingStatus]struct{})
+		}
+		h.watchers[req.Service][ch] = struct{}{}
+	}
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.watchers[req.Service], ch)
+		if len(h.watchers[req.Service]) == 0 {
+			delete(h.watchers, req.Service)
+		}
+	}()
+
+	var last servingStatus = -1
+	for {
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+
+		case st, ok := <-ch:
+			// The last status is delivered before the channel reports closed
+			if !ok {
+				return nil
+			}
+			if st == last {
+				continue
+			}
+			if err := stream.Send(&healthgrpc.HealthCheckResponse{Status: st}); err != nil {
+				return err
+			}
+			last = st
+		}
+	}
+}
+
+// monitorStorage reports the server NOT_SERVING while the storage backend fails its check
+func (s *server) monitorStorage(interval time.Duration) {
+	service := userv1.UserService_ServiceDesc.ServiceName
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	healthy := true
+	for range ticker.C {
+		err := s.blobs.Check()
+		switch {
+		case err != nil && healthy:
+			slog.Error("Upload storage is unhealthy, reporting NOT_SERVING", "error", err)
+			s.health.setStatus(service, healthgrpc.HealthCheckResponse_NOT_SERVING)
+			s.health.setStatus("", healthgrpc.HealthCheckResponse_NOT_SERVING)
+		case err == nil && !healthy:
+			slog.Info("Upload storage recovered, reporting SERVING")
+			s.health.setStatus(service, healthgrpc.HealthCheckResponse_SERVING)
+			s.health.setStatus("", healthgrpc.HealthCheckResponse_SERVING)
+		}
+		healthy = err == nil
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"grpc-go-learning/internal/requestid"
+	"grpc-go-learning/internal/rpcstatus"
+	"grpc-go-learning/internal/tracing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/proto"
+)
+
+// logLevelOff disables logging of a method
+const logLevelOff = "off"
+
+// setupLogging installs the slog handler for the configured format, dropping messages
+// below the configured level; log.Printf output goes through it as well
+func setupLogging(c *serverConfig) {
+	level, _ := parseLogLevel(c.LogLevel) // checked by validate
+
+	options := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler = slog.NewTextHandler(os.Stderr, options)
+	if c.LogFormat == "json" {
+		handler = slog.NewJSONHandler(os.Stderr, options)
+	}
+	slog.SetDefault(slog.New(contextHandler{handler}))
+}
+
+// contextHandler adds the request and trace IDs of the RPC being served to records logged with its context
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := requestid.FromContext(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if span := tracing.SpanFromContext(ctx); span != nil {
+		r.AddAttrs(slog.String("trace_id", span.TraceID()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// rpcLogger logs each finished RPC with its method, peer, duration, status code and message sizes
+type rpcLogger struct {
+	// levels holds the level successful calls are logged at, by full method
+	// ("/user.v1.UserService/GetUser") or by service ("/user.v1.UserService/*")
+	levels map[string]slog.Level
+	off    map[string]bool // methods or services that aren't logged at all
+}
+
+// newRPCLogger builds a logger from the log_methods setting
+func newRPCLogger(methods map[string]string) *rpcLogger {
+	l := &rpcLogger{
+		levels: make(map[string]slog.Level),
+		off:    make(map[string]bool),
+	}
+	for method, level := range methods {
+		if level == logLevelOff {
+			l.off[method] = true
+			continue
+		}
+		l.levels[method], _ = parseLogLevel(level) // checked by validate
+	}
+	return l
+}
+
+// level returns the level for successful calls to fullMethod, and false if the method isn't logged
+func (l *rpcLogger) level(fullMethod string) (slog.Level, bool) {
+	service := fullMethod[:strings.LastIndex(fullMethod, "/")+1] + "*"
+	for _, key := range []string{fullMethod, service} {
+		if l.off[key] {
+			return 0, false
+		}
+		if level, ok := l.levels[key]; ok {
+			return level, true
+		}
+	}
+	return slog.LevelInfo, true
+}
+
+// codeLevel is the least level a call ending with code is logged at:
+// caller mistakes are warnings, server-side failures are errors
+func codeLevel(code codes.Code) slog.Level {
+	switch code {
+	case codes.OK:
+		return slog.LevelDebug
+	case codes.Canceled:
+		// Clients going away is how streams normally end
+		return slog.LevelInfo
+	case codes.Unknown, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		return slog.LevelError
+	default:
+		return slog.LevelWarn
+	}
+}
+
+func (l *rpcLogger) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	level, ok := l.level(info.FullMethod)
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	start := time.Now()
+	resp, err := handler(ctx, req)
+
+	l.log(ctx, level, "unary RPC finished", info.FullMethod, start, err,
+		slog.Int("request_bytes", messageSize(req)),
+		slog.Int("response_bytes", messageSize(resp)),
+	)
+	return resp, err
+}
+
+func (l *rpcLogger) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	level, ok := l.level(info.FullMethod)
+	if !ok {
+		return handler(srv, ss)
+	}
+
+	ctx := ss.Context()
+	slog.DebugContext(ctx, "stream RPC started", slog.String("grpc.method", info.FullMethod), peerAttr(ctx))
+
+	start := time.Now()
+	counted := &countingStream{ServerStream: ss}
+	err := handler(srv, counted)
+
+	l.log(ctx, level, "stream RPC finished", info.FullMethod, start, err,
+		slog.Int64("messages_received", counted.recvMsgs.Load()),
+		slog.Int64("messages_sent", counted.sentMsgs.Load()),
+		slog.Int64("bytes_received", counted.recvBytes.Load()),
+		slog.Int64("bytes_sent", counted.sentBytes.Load()),
+	)
+	return err
+}
+
+// log writes the record for a finished RPC; failures are raised to at least their code's level
+func (l *rpcLogger) log(ctx context.Context, level slog.Level, msg, fullMethod string, start time.Time, err error, sizes ...slog.Attr) {
+	st := rpcstatus.Convert(err)
+	if codeLevel(st.Code()) > level {
+		level = codeLevel(st.Code())
+	}
+
+	attrs := []slog.Attr{
+		slog.String("grpc.method", fullMethod),
+		peerAttr(ctx),
+		slog.Duration("duration", time.Since(start)),
+		slog.String("grpc.code", st.Code().String()),
+	}
+	attrs = append(attrs, sizes...)
+	if err != nil {
+		attrs = append(attrs, slog.String("error", st.Message()))
+	}
+	slog.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// peerAttr returns the caller's address
+func peerAttr(ctx context.Context) slog.Attr {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return slog.String("peer", p.Addr.String())
+	}
+	return slog.String("peer", "unknown")
+}
+
+// messageSize returns the encoded size of a protobuf message, 0 for anything else
+func messageSize(m any) int {
+	if pm, ok := m.(proto.Message); ok {
+		return proto.Size(pm)
+	}
+	return 0
+}
+
+// countingStream counts the messages passing through a server stream.
+// Handlers may receive and send on different goroutines, hence the atomics.
+type countingStream struct {
+	grpc.ServerStream
+	recvMsgs, sentMsgs   atomic.Int64
+	recvBytes, sentBytes atomic.Int64
+}
+
+func (s *countingStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.recvMsgs.Add(1)
+		s.recvBytes.Add(int64(messageSize(m)))
+	}
+	return err
+}
+
+func (s *countingStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sentMsgs.Add(1)
+		s.sentBytes.Add(int64(messageSize(m)))
+	}
+	return err
+}
+
+// parseMethodLevels parses the log_methods setting from "method=level,..." form
+func parseMethodLevels(value string) (map[string]string, error) {
+	levels := make(map[string]string)
+	for _, item := range splitList(value) {
+		method, level, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q is not method=level", item)
+		}
+		levels[strings.TrimSpace(method)] = strings.TrimSpace(level)
+	}
+	return levels, nil
+}
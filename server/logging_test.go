@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRPCLoggerCodesContextErrors(t *testing.T) {
+	var out bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	tests := []struct {
+		err       error
+		wantCode  string
+		wantLevel string
+	}{
+		{err: nil, wantCode: "OK", wantLevel: "INFO"},
+		{err: context.Canceled, wantCode: "Canceled", wantLevel: "INFO"},
+		{err: context.DeadlineExceeded, wantCode: "DeadlineExceeded", wantLevel: "WARN"},
+		{err: status.Error(codes.NotFound, "gone"), wantCode: "NotFound", wantLevel: "WARN"},
+		{err: errors.New("boom"), wantCode: "Unknown", wantLevel: "ERROR"},
+	}
+
+	l := newRPCLogger(nil)
+	for _, tt := range tests {
+		out.Reset()
+		l.log(context.Background(), slog.LevelInfo, "unary RPC finished", "/user.v1.UserService/GetUser", time.Now(), tt.err)
+
+		var record struct {
+			Level string `json:"level"`
+			Code  string `json:"grpc.code"`
+		}
+		if err := json.Unmarshal(out.Bytes(), &record); err != nil {
+			t.Fatalf("parse log line %q: %v", out.String(), err)
+		}
+		if record.Code != tt.wantCode || record.Level != tt.wantLevel {
+			t.Errorf("err %v logged as %s at %s, want %s at %s", tt.err, record.Code, record.Level, tt.wantCode, tt.wantLevel)
+		}
+	}
+}
+
+func TestRecvError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if code := status.Code(recvError(ctx, errors.New("connection reset"))); code != codes.Internal {
+		t.Fatalf("transport failure = %v, want Internal", code)
+	}
+	cancel()
+	if code := status.Code(recvError(ctx, status.Error(codes.Canceled, "context canceled"))); code != codes.Canceled {
+		t.Fatalf("client went away = %v, want Canceled", code)
+	}
+}
@@ -4,31 +4,52 @@ import (
 	"context"
 	"fmt"
 	userv1 "grpc-go-learning/gen/go/user/v1/user"
-	"io"
+	"grpc-go-learning/internal/tracing"
 	"log"
+	"log/slog"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 )
 
 //server implements UserServiceServer interface
 type server struct {
 	userv1.UnimplementedUserServiceServer
+	mu sync.RWMutex // guards users
 	users map[string]*userv1.User //in-memory strorage
+	notifications *notificationHub
+	jobs *notificationJobs
+	blobs blobStore
+	uploads *uploadIndex
+	pending *pendingUploads
+	quotas *quotaTracker
+	multipart *multipartUploads
+	allowedContentTypes []string
+	processing *processingPipeline
+	health *healthService
+	metrics *serverMetrics
 }
 
 //GetUser implement the GetUser RPC method
 func (s *server) GetUser(ctx context.Context, req *userv1.GetUserRequest)(*userv1.GetUserResponse, error)  {
-	log.Printf("GetUser called with user_id: %s", req.UserId)
-
 	if req.UserId == ""{
 		return nil, status.Error(codes.InvalidArgument, "user_id is required")
 	}
 
+	s.mu.RLock()
 	user, exists := s.users[req.UserId]
+	s.mu.RUnlock()
 	if !exists {
 		return nil, status.Errorf(codes.NotFound, "user with id %s notfound", req.UserId)
 	}
@@ -41,8 +62,6 @@ func (s *server) GetUser(ctx context.Context, req *userv1.GetUserRequest)(*userv
 
 //CreateUser implement the CreateUser RPC method
 func (s *server) CreateUser(ctx context.Context, req *userv1.CreateUSerRequest) (*userv1.CreateUserResponse, error)  {
-	log.Printf("CreateUser called with name: %s, email: %s", req.Name, req.Email)
-
 	if req.Name == "" {
 		return nil, status.Error(codes.InvalidArgument, "name id required")
 	}
@@ -51,6 +70,9 @@ func (s *server) CreateUser(ctx context.Context, req *userv1.CreateUSerRequest)
 		return nil, status.Error(codes.InvalidArgument, "email is required")
 	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	//Generate user Id
 	userID := fmt.Sprintf("user_%d", len(s.users)+1)
 
@@ -66,137 +88,264 @@ func (s *server) CreateUser(ctx context.Context, req *userv1.CreateUSerRequest)
 	//Store user
 	s.users[userID] = user
 
-	log.Printf("User created successfully: %s", userID)
+	slog.InfoContext(ctx, "User created", "user_id", userID)
 	return &userv1.CreateUserResponse{
 		User: user,
 	}, nil
 }
 
 
+// StreamNotifications delivers what PublishNotification and broadcasts send to the user.
+// It replaces the old demo that streamed ten simulated notifications and then ended.
 func (s *server) StreamNotifications(req *userv1.StreamNotificationsRequest, stream userv1.UserService_StreamNotificationsServer) error {
-	log.Printf("StreamNotifications called for user_id: %s", req.UserId)
-
 	// Validate request
   if req.UserId == "" {
       return status.Error(codes.InvalidArgument, "user_id is required")
   }
 
-	// Simulate sending 10 notifications
-	for i:=1; i<=10; i++ {
+	notifications, unsubscribe := s.notifications.subscribe(req.UserId)
+	defer unsubscribe()
+
+	for {
+		select {
 		// Check if client has disconnected
-		if stream.Context().Err() != nil {
-			log.Printf("Client disconnected: %v", stream.Context().Err())
-      return stream.Context().Err()
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+
+		case notification, ok := <-notifications:
+			// The hub closes subscriptions when the server shuts down
+			if !ok {
+				slog.InfoContext(stream.Context(), "Server shutting down, ending notification stream", "user_id", req.UserId)
+				return nil
+			}
+
+			// Send Notification
+			if err := stream.Send(notification); err != nil {
+				return status.Errorf(codes.Internal, "failed to send notification: %v", err)
+			}
+
+			slog.DebugContext(stream.Context(), "Sent notification", "notification_id", notification.NotificationId, "user_id", req.UserId)
 		}
+	}
+}
 
-		// Create a notification
-    notification := &userv1.Notification{
-        NotificationId: fmt.Sprintf("notif_%d", i),
-        UserId:         req.UserId,
-        Title:          fmt.Sprintf("Notification #%d", i),
-        Message:        fmt.Sprintf("This is notification number %d for user %s", i, req.UserId),
-        Type:           userv1.NotificationType_NOTIFICATION_TYPE_INFO,
-        Timestamp:      time.Now().Unix(),
-    }
-
-
-		// Send Notification
-		if err := stream.Send(notification); err != nil{
-			log.Printf("Failed to send notification: %v", err)
-			return status.Errorf(codes.Internal, "failed to send notification: %v", err)
-		}
 
-		log.Printf("Sent notification #%d to user %s", i, req.UserId)
+// PublishNotification delivers a notification to the user's active streams
+func (s *server) PublishNotification(ctx context.Context, req *userv1.PublishNotificationRequest) (*userv1.PublishNotificationResponse, error) {
+	if req.Notification == nil {
+		return nil, status.Error(codes.InvalidArgument, "notification is required")
+	}
+
+	if req.Audience != nil {
+		return s.publishToAudience(ctx, req)
+	}
+	if req.Notification.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
 
-		// Simulate delay between notifications (1 second)
-    time.Sleep(time.Second * 1)
+	result := s.notifications.publish(req.Notification)
+	if result.deduplicated {
+		slog.InfoContext(ctx, "Dropped duplicate notification",
+			"user_id", req.Notification.UserId, "dedup_key", req.Notification.DedupKey, "notification_id", result.notificationID)
+		return &userv1.PublishNotificationResponse{
+			NotificationId: result.notificationID,
+			Status:         userv1.PublishStatus_PUBLISH_STATUS_DEDUPLICATED,
+		}, nil
 	}
 
-	log.Printf("Finished streaming notifications for user %s", req.UserId)
-  return nil
+	return &userv1.PublishNotificationResponse{
+		NotificationId: result.notificationID,
+		Status:         userv1.PublishStatus_PUBLISH_STATUS_ACCEPTED,
+	}, nil
 }
 
 
-// UploadUserData implements client-side streaming
-func (S *server)  UploadUserData(stream userv1.UserService_UploadUserDataServer) error {
-	log.Println("UploadUserData called")
-
-	var (
-    	userID       string
-    	filename     string
-    	totalSize    int64
-    	bytesReceived int64
-    	chunkCount   int
-  )
+// publishToAudience expands the audience against the user store and delivers in the background
+func (s *server) publishToAudience(ctx context.Context, req *userv1.PublishNotificationRequest) (*userv1.PublishNotificationResponse, error) {
+	if err := validateAudience(req.Audience); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid audience: %v", err)
+	}
 
-	// Receive messages from client
-	for {
-		req, err := stream.Recv()
-
-		// Check if client finished sending
-		if err == io.EOF {
-			log.Printf("Client finished sending. Received %d bytes in %d chunks", bytesReceived, chunkCount)
-
-			// Send final response to client
-			return stream.SendAndClose(&userv1.UploadUserDataResponse{
-				UploadId: fmt.Sprintf("upload_%s_%d", userID, time.Now().Unix()),
-				BytesReceived: bytesReceived,
-				Success:       true,
-			})
+	var recipients []string
+	s.mu.RLock()
+	for userID, user := range s.users {
+		if matchesAudience(user, req.Audience) {
+			recipients = append(recipients, userID)
 		}
+	}
+	s.mu.RUnlock()
+
+	jobID := s.jobs.start(len(recipients))
+	// The job outlives the RPC but keeps its request and trace IDs
+	go s.deliverToAudience(context.WithoutCancel(ctx), jobID, req.Notification, recipients)
+
+	slog.InfoContext(ctx, "Queued notification job", "job_id", jobID, "recipients", len(recipients))
+	return &userv1.PublishNotificationResponse{
+		Status: userv1.PublishStatus_PUBLISH_STATUS_QUEUED,
+		JobId:  jobID,
+	}, nil
+}
+
 
-		// Check for errors
-    if err != nil {
-      log.Printf("Error receiving data: %v", err)
-      return status.Errorf(codes.Internal, "failed to receive data: %v", err)
-    }
-
-		// Process the received message
-		switch data := req.Data.(type) {
-    case *userv1.UploadUserDataRequest_Metadata:
-      // First message: metadata
-      userID = data.Metadata.UserId
-      filename = data.Metadata.Filename
-      totalSize = data.Metadata.TotalSize
-      log.Printf("Receiving upload for user %s, file: %s, size: %d bytes", userID, filename, totalSize)
-
-    case *userv1.UploadUserDataRequest_Chunk:
-      // Subsequent messages: data chunks
-      chunkSize := len(data.Chunk.Data)
-      bytesReceived += int64(chunkSize)
-      chunkCount++
-      log.Printf("Received chunk #%d: %d bytes (total: %d/%d)", 
-      data.Chunk.ChunkNumber, chunkSize, bytesReceived, totalSize)
-
-    default:
-      return status.Error(codes.InvalidArgument, "unknown data type")
-    }
+// GetNotificationJob reports the progress of an audience publish
+func (s *server) GetNotificationJob(ctx context.Context, req *userv1.GetNotificationJobRequest) (*userv1.NotificationJob, error) {
+	if req.JobId == "" {
+		return nil, status.Error(codes.InvalidArgument, "job_id is required")
 	}
+
+	job, exists := s.jobs.get(req.JobId)
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "notification job %s not found", req.JobId)
+	}
+
+	return job, nil
 }
 
 
 func main()  {
-	// Create TCP listener on port 50051
-	lis, err := net.Listen("tcp", ":50051")
+	config, err := loadConfig(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	setupLogging(config)
+
+	// Create TCP listener
+	lis, err := net.Listen("tcp", config.ListenAddr)
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
+	// Spans are exported as JSON lines, or only propagated when no exporter is set
+	exporter, err := tracing.NewExporter(config.Tracing.Exporter, config.Tracing.File)
+	if err != nil {
+		log.Fatalf("Failed to create trace exporter: %v", err)
+	}
+	tracer := tracing.NewTracer("user-server", exporter)
+
 	//Create gRPC server
-	grpcServer := grpc.NewServer()
+	// Tracing and request IDs run outermost so log lines carry both IDs;
+	// recovery runs innermost so a recovered panic is logged and counted as Internal
+	logger := newRPCLogger(config.LogMethods)
+	metrics := newServerMetrics()
+	recoverer := &panicRecoverer{}
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(tracer.UnaryServer, requestIDUnary, logger.unary, metrics.unary, recoverer.unary),
+		grpc.ChainStreamInterceptor(tracer.StreamServer, requestIDStream, logger.stream, metrics.stream, recoverer.stream),
+	}
+	// TLS, and mutual TLS with a client CA; rotated files are picked up without a restart
+	if config.TLS.CertFile != "" {
+		certs, err := newCertReloader(config.TLS)
+		if err != nil {
+			log.Fatalf("Failed to load TLS certificate: %v", err)
+		}
+		if config.TLS.ReloadInterval > 0 {
+			go certs.watch(time.Duration(config.TLS.ReloadInterval))
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(certs.tlsConfig())))
+		if config.TLS.CallerFromCert {
+			opts = append(opts, grpc.ChainUnaryInterceptor(certCallerUnary), grpc.ChainStreamInterceptor(certCallerStream))
+		}
+	}
+	grpcServer := grpc.NewServer(opts...)
+
+	// Uploaded data goes to the configured storage backend
+	blobs, err := config.openBlobStore()
+	if err != nil {
+		log.Fatalf("Failed to open upload store: %v", err)
+	}
+	uploads, err := config.openUploadIndex()
+	if err != nil {
+		log.Fatalf("Failed to open upload index: %v", err)
+	}
 
 	// Create our server implementation with in-memory storage
 	userServer := &server{
 		users: make(map[string]*userv1.User),
+		notifications: newNotificationHub(time.Duration(config.Limits.DedupWindow)),
+		jobs: newNotificationJobs(jobRetention),
+		blobs: blobs,
+		uploads: uploads,
+		pending: newPendingUploads(time.Duration(config.Limits.IncompleteUploadTTL)),
+		quotas: newQuotaTracker(config.Limits.MaxUploadSize, config.Limits.UserQuota),
+		multipart: newMultipartUploads(time.Duration(config.Limits.IncompleteUploadTTL)),
+		allowedContentTypes: config.Limits.AllowedContentTypes,
+		processing: newProcessingPipeline(),
+		health: newHealthService(userv1.UserService_ServiceDesc.ServiceName),
+		metrics: metrics,
+	}
+
+	metrics.registerFunc("notification_subscribers", "gauge", "Open StreamNotifications subscriptions.", func() float64 {
+		return float64(userServer.notifications.subscriberCount())
+	})
+	metrics.registerFunc("grpc_server_panics_recovered_total", "counter", "Handler panics converted to Internal errors.", func() float64 {
+		return float64(recoverer.panics.Load())
+	})
+
+	// Uploads stored by an earlier run still count against their users' quotas
+	for _, record := range userServer.uploads.all() {
+		userServer.quotas.restore(record.userID, record.size)
 	}
 
+	// Rebuild the reference counts from the stored upload index. Only data no indexed
+	// upload refers to is freed: parts of multipart uploads that weren't completed
+	reclaimed, err := blobs.Reclaim(userServer.uploads.digests())
+	if err != nil {
+		slog.Error("Failed to reclaim unreferenced upload data", "error", err)
+	}
+	if reclaimed > 0 {
+		slog.Info("Reclaimed unreferenced upload data", "objects", reclaimed)
+	}
+
+	// Post-upload processors, by content type
+	userServer.processing.register("application/json", newProfileImporter(userServer))
+
+	// Discard incomplete uploads that are never resumed, old notification jobs and expired dedup keys
+	go userServer.pending.runCollector(uploadGCInterval)
+	go userServer.multipart.runCollector(userServer, uploadGCInterval)
+	go userServer.jobs.runCollector(jobGCInterval)
+	go userServer.notifications.runCollector(dedupGCInterval)
+
 	// register our server with gRPC server
 	userv1.RegisterUserServiceServer(grpcServer, userServer)
+	healthgrpc.RegisterHealthServer(grpcServer, userServer.health)
+
+	// Reflection (v1 and v1alpha) lets tools discover the API without the .proto files
+	if config.Reflection {
+		reflection.Register(grpcServer)
+		slog.Info("Server reflection enabled")
+	}
 
-	log.Println("🚀 gRPC server listening on :50051")
+	// Report NOT_SERVING while uploads can't be stored
+	go userServer.monitorStorage(storageCheckInterval)
+
+	slog.Info("🚀 gRPC server listening", "addr", config.ListenAddr)
+
+	// Metrics are served over plain HTTP on their own port
+	var metricsServer *http.Server
+	if config.MetricsAddr != "" {
+		metricsServer = metrics.serve(config.MetricsAddr)
+		slog.Info("📊 Metrics endpoint listening", "addr", config.MetricsAddr, "path", "/metrics")
+	}
 
 	// start server
-	if err := grpcServer.Serve(lis); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- grpcServer.Serve(lis)
+	}()
+
+	select {
+	case err := <-serveErr:
 		log.Fatalf("Failed to serve: %v", err)
+	case <-ctx.Done():
+	}
+
+	// A second signal kills the process without waiting for the drain
+	stop()
+	userServer.shutdown(grpcServer, metricsServer, time.Duration(config.DrainDelay), time.Duration(config.ShutdownTimeout))
+	if err := tracer.Shutdown(); err != nil {
+		slog.Error("Failed to close trace exporter", "error", err)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"grpc-go-learning/internal/rpcstatus"
+
+	"google.golang.org/grpc"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the RPC latency histogram
+var latencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60}
+
+// rpcKey identifies an RPC method and, for counters by result, its status code
+type rpcKey struct {
+	method string // full method, "/package.Service/Method"
+	code   string
+}
+
+// histogram counts observations into cumulative latencyBuckets
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative; the last is +Inf
+	sum    float64
+	count  uint64
+}
+
+func (h *histogram) observe(v float64) {
+	i, _ := slices.BinarySearch(latencyBuckets, v)
+	h.counts[i]++
+	h.sum += v
+	h.count++
+}
+
+// gaugeFunc is a gauge or counter whose value is read from elsewhere at scrape time
+type gaugeFunc struct {
+	name, help, kind string
+	value            func() float64
+}
+
+// serverMetrics collects RPC and upload metrics and serves them in the Prometheus text format
+type serverMetrics struct {
+	mu       sync.Mutex
+	handled  map[rpcKey]uint64     // finished RPCs by method and code
+	latency  map[string]*histogram // method -> handling time
+	inFlight map[string]int64      // method -> open streams
+
+	uploadBytesReceived atomic.Int64 // chunk data accepted into uploads, complete or not
+	uploadBytesStored   atomic.Int64 // size of completed uploads
+
+	funcs []gaugeFunc
+}
+
+func newServerMetrics() *serverMetrics {
+	return &serverMetrics{
+		handled:  make(map[rpcKey]uint64),
+		latency:  make(map[string]*histogram),
+		inFlight: make(map[string]int64),
+	}
+}
+
+// registerFunc exposes a value owned by another component; call before serving
+func (m *serverMetrics) registerFunc(name, kind, help string, value func() float64) {
+	m.funcs = append(m.funcs, gaugeFunc{name: name, help: help, kind: kind, value: value})
+}
+
+func (m *serverMetrics) observe(method string, err error, elapsed time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.handled[rpcKey{method: method, code: rpcstatus.Code(err).String()}]++
+	h, ok := m.latency[method]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(latencyBuckets)+1)}
+		m.latency[method] = h
+	}
+	h.observe(elapsed.Seconds())
+}
+
+func (m *serverMetrics) streamOpened(method string, delta int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inFlight[method] += delta
+}
+
+func (m *serverMetrics) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	m.observe(info.FullMethod, err, time.Since(start))
+	return resp, err
+}
+
+func (m *serverMetrics) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	m.streamOpened(info.FullMethod, 1)
+	defer m.streamOpened(info.FullMethod, -1)
+
+	start := time.Now()
+	err := handler(srv, ss)
+	m.observe(info.FullMethod, err, time.Since(start))
+	return err
+}
+
+// ServeHTTP writes every metric in the Prometheus text exposition format
+func (m *serverMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	out := bufio.NewWriter(w)
+	defer out.Flush()
+
+	m.mu.Lock()
+	handled := make([]rpcKey, 0, len(m.handled))
+	for key := range m.handled {
+		handled = append(handled, key)
+	}
+	slices.SortFunc(handled, func(a, b rpcKey) int {
+		return strings.Compare(a.method+" "+a.code, b.method+" "+b.code)
+	})
+	writeHeader(out, "grpc_server_handled_total", "counter", "RPCs completed on the server, by method and status code.")
+	for _, key := range handled {
+		fmt.Fprintf(out, "grpc_server_handled_total{%s,grpc_code=%q} %d\n", methodLabels(key.method), key.code, m.handled[key])
+	}
+
+	writeHeader(out, "grpc_server_handling_seconds", "histogram", "Time taken to complete RPCs, by method.")
+	for _, method := range sortedKeys(m.latency) {
+		h := m.latency[method]
+		labels := methodLabels(method)
+		var cumulative uint64
+		for i, bound := range latencyBuckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(out, "grpc_server_handling_seconds_bucket{%s,le=%q} %d\n", labels, formatFloat(bound), cumulative)
+		}
+		fmt.Fprintf(out, "grpc_server_handling_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, h.count)
+		fmt.Fprintf(out, "grpc_server_handling_seconds_sum{%s} %s\n", labels, formatFloat(h.sum))
+		fmt.Fprintf(out, "grpc_server_handling_seconds_count{%s} %d\n", labels, h.count)
+	}
+
+	writeHeader(out, "grpc_server_streams_in_flight", "gauge", "Streaming RPCs currently open, by method.")
+	for _, method := range sortedKeys(m.inFlight) {
+		fmt.Fprintf(out, "grpc_server_streams_in_flight{%s} %d\n", methodLabels(method), m.inFlight[method])
+	}
+	m.mu.Unlock()
+
+	writeHeader(out, "upload_received_bytes_total", "counter", "Chunk data accepted into uploads, including ones never completed.")
+	fmt.Fprintf(out, "upload_received_bytes_total %d\n", m.uploadBytesReceived.Load())
+	writeHeader(out, "upload_stored_bytes_total", "counter", "Size of completed uploads.")
+	fmt.Fprintf(out, "upload_stored_bytes_total %d\n", m.uploadBytesStored.Load())
+
+	for _, f := range m.funcs {
+		writeHeader(out, f.name, f.kind, f.help)
+		fmt.Fprintf(out, "%s %s\n", f.name, formatFloat(f.value()))
+	}
+}
+
+// serve exposes the metrics on addr until the returned server is shut down
+func (m *serverMetrics) serve(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", m)
+
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("Metrics endpoint failed", "addr", addr, "error", err)
+		}
+	}()
+	return srv
+}
+
+func writeHeader(out *bufio.Writer, name, kind, help string) {
+	fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// methodLabels splits "/package.Service/Method" into grpc_service and grpc_method labels
+func methodLabels(fullMethod string) string {
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return fmt.Sprintf("grpc_service=%q,grpc_method=%q", service, method)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
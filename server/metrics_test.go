@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetricsCountContextErrorsByTheirCode(t *testing.T) {
+	m := newServerMetrics()
+	const method = "/user.v1.UserService/StreamNotifications"
+	m.observe(method, context.Canceled, time.Millisecond)
+	m.observe(method, context.DeadlineExceeded, time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	out := rec.Body.String()
+
+	for _, code := range []string{"Canceled", "DeadlineExceeded"} {
+		if !strings.Contains(out, `grpc_code="`+code+`"} 1`) {
+			t.Errorf("no grpc_code=%q sample in:\n%s", code, out)
+		}
+	}
+	if strings.Contains(out, `grpc_code="Unknown"`) {
+		t.Errorf("context errors counted as Unknown:\n%s", out)
+	}
+}
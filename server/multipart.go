@@ -0,0 +1,514 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	userv1 "grpc-go-learning/gen/go/user/v1/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxMultipartParts is the highest part number accepted by UploadPart
+const maxMultipartParts = 10000
+
+// multipartPart is a part stored as its own blob until the upload is completed
+type multipartPart struct {
+	size   int64
+	sha256 string
+}
+
+// multipartUpload is an upload whose parts arrive on separate UploadPart streams
+type multipartUpload struct {
+	uploadID    string
+	metadata    *userv1.UserMetadata
+	expectedSHA string
+
+	mu         sync.Mutex // guards the fields below
+	parts      map[int32]*multipartPart
+	streaming  int64 // bytes received by parts still being streamed
+	active     int   // number of UploadPart streams attached
+	completing bool  // set once CompleteUpload starts; no new parts are accepted
+	updatedAt  time.Time
+}
+
+// storedBytes is the size of the stored parts plus the parts being streamed; u.mu must be held
+func (u *multipartUpload) storedBytes() int64 {
+	total := u.streaming
+	for _, part := range u.parts {
+		total += part.size
+	}
+	return total
+}
+
+// multipartUploads holds the multipart uploads that haven't been completed
+type multipartUploads struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	uploads map[string]*multipartUpload // upload_id -> upload
+}
+
+func newMultipartUploads(ttl time.Duration) *multipartUploads {
+	return &multipartUploads{
+		ttl:     ttl,
+		uploads: make(map[string]*multipartUpload),
+	}
+}
+
+func (m *multipartUploads) add(upload *multipartUpload) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.uploads[upload.uploadID] = upload
+}
+
+// get returns uploadID if it belongs to userID
+func (m *multipartUploads) get(uploadID, userID string) (*multipartUpload, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	upload, exists := m.uploads[uploadID]
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "multipart upload %s not found", uploadID)
+	}
+	if upload.metadata.UserId != userID {
+		return nil, status.Errorf(codes.PermissionDenied, "upload %s belongs to another user", uploadID)
+	}
+	return upload, nil
+}
+
+func (m *multipartUploads) remove(uploadID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.uploads, uploadID)
+}
+
+// collectExpired discards idle multipart uploads older than the TTL, releasing their parts
+func (m *multipartUploads) collectExpired(s *server, now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for uploadID, upload := range m.uploads {
+		upload.mu.Lock()
+		expired := upload.active == 0 && !upload.completing && now.Sub(upload.updatedAt) > m.ttl
+		if expired {
+			for partNumber, part := range upload.parts {
+				s.discardPart(upload, part)
+				delete(upload.parts, partNumber)
+			}
+			delete(m.uploads, uploadID)
+			slog.Info("Discarded expired multipart upload", "upload_id", uploadID)
+		}
+		upload.mu.Unlock()
+	}
+}
+
+// runCollector calls collectExpired every interval, forever
+func (m *multipartUploads) runCollector(s *server, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		m.collectExpired(s, now)
+	}
+}
+
+// discardPart releases the blob and quota held by part
+func (s *server) discardPart(upload *multipartUpload, part *multipartPart) {
+	s.quotas.release(upload.metadata.UserId, part.size)
+	if err := s.blobs.Release(part.sha256); err != nil {
+		slog.Error("Failed to release part blob", "upload_id", upload.uploadID, "sha256", part.sha256, "error", err)
+	}
+}
+
+// InitiateUpload starts a multipart upload
+func (s *server) InitiateUpload(ctx context.Context, req *userv1.InitiateUploadRequest) (*userv1.InitiateUploadResponse, error) {
+	expectedSHA, err := s.validateMetadata(ctx, req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	if req.Metadata.ResumeUploadId != "" {
+		return nil, uploadViolation(codes.InvalidArgument, "metadata.resume_upload_id", "multipart uploads are resumed by re-sending parts")
+	}
+	if err := s.admitUpload(req.Metadata); err != nil {
+		return nil, err
+	}
+
+	uploadID, err := newUploadID()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate upload id: %v", err)
+	}
+
+	s.multipart.add(&multipartUpload{
+		uploadID:    uploadID,
+		metadata:    req.Metadata,
+		expectedSHA: expectedSHA,
+		parts:       make(map[int32]*multipartPart),
+		updatedAt:   time.Now(),
+	})
+
+	slog.InfoContext(ctx, "Initiated multipart upload", "upload_id", uploadID, "user_id", req.Metadata.UserId,
+		"filename", req.Metadata.Filename, "total_size", req.Metadata.TotalSize)
+	return &userv1.InitiateUploadResponse{
+		UploadId: uploadID,
+		MaxParts: maxMultipartParts,
+	}, nil
+}
+
+// UploadPart implements client-side streaming of one part
+func (s *server) UploadPart(stream userv1.UserService_UploadPartServer) error {
+	req, err := stream.Recv()
+	if err == io.EOF {
+		return uploadViolation(codes.InvalidArgument, "header", "stream closed before the part header was sent")
+	}
+	if err != nil {
+		return recvError(stream.Context(), err)
+	}
+
+	header := req.GetHeader()
+	if header == nil {
+		return uploadViolation(codes.InvalidArgument, "header", "the part header must be sent before any chunk")
+	}
+	if header.UploadId == "" {
+		return uploadViolation(codes.InvalidArgument, "header.upload_id", "upload_id is required")
+	}
+	if header.UserId == "" {
+		return uploadViolation(codes.InvalidArgument, "header.user_id", "user_id is required")
+	}
+	if header.PartNumber < 1 || header.PartNumber > maxMultipartParts {
+		return uploadViolation(codes.InvalidArgument, "header.part_number",
+			fmt.Sprintf("part_number must be between 1 and %d, got %d", maxMultipartParts, header.PartNumber))
+	}
+	if err := s.checkUploader(stream.Context(), header.UserId); err != nil {
+		return err
+	}
+
+	upload, err := s.multipart.get(header.UploadId, header.UserId)
+	if err != nil {
+		return err
+	}
+
+	upload.mu.Lock()
+	if upload.completing {
+		upload.mu.Unlock()
+		return status.Errorf(codes.FailedPrecondition, "upload %s is being completed", header.UploadId)
+	}
+	upload.active++
+	upload.mu.Unlock()
+
+	part, err := s.receivePart(stream, upload, header.PartNumber)
+
+	// CompleteUpload refuses to start while active > 0, so the part can always be registered
+	upload.mu.Lock()
+	upload.active--
+	upload.updatedAt = time.Now()
+	if err == nil {
+		upload.streaming -= part.size
+		if previous, exists := upload.parts[header.PartNumber]; exists {
+			s.discardPart(upload, previous)
+		}
+		upload.parts[header.PartNumber] = part
+	}
+	upload.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(stream.Context(), "Stored part", "upload_id", header.UploadId, "part_number", header.PartNumber, "bytes", part.size)
+	return stream.SendAndClose(&userv1.UploadPartResponse{
+		UploadId:   header.UploadId,
+		PartNumber: header.PartNumber,
+		Size:       part.size,
+		Sha256:     part.sha256,
+	})
+}
+
+// receivePart stores the chunks of one part in its own blob
+func (s *server) receivePart(stream userv1.UserService_UploadPartServer, upload *multipartUpload, partNumber int32) (*multipartPart, error) {
+	ctx := stream.Context()
+	userID := upload.metadata.UserId
+
+	blob, err := s.blobs.Create()
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to create blob for part", "upload_id", upload.uploadID, "part_number", partNumber, "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to store part: %v", err)
+	}
+
+	var (
+		size       int64
+		chunkCount int32
+		committed  bool
+	)
+	// Until UploadPart registers the part its bytes count as streaming
+	defer func() {
+		if !committed {
+			blob.Abort()
+			s.quotas.release(userID, size)
+			upload.mu.Lock()
+			upload.streaming -= size
+			upload.mu.Unlock()
+		}
+	}()
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, recvError(ctx, err)
+		}
+
+		chunk := req.GetChunk()
+		if chunk == nil {
+			return nil, uploadViolation(codes.InvalidArgument, "header", "the part header must be sent exactly once")
+		}
+		if chunk.ChunkNumber != chunkCount {
+			return nil, uploadViolation(codes.InvalidArgument, "chunk.chunk_number",
+				fmt.Sprintf("expected chunk %d, got %d", chunkCount, chunk.ChunkNumber))
+		}
+		if err := verifyChunkCRC(chunk.Data, chunk.Crc32C); err != nil {
+			return nil, status.Errorf(codes.DataLoss, "chunk %d of part %d is corrupt: %v", chunk.ChunkNumber, partNumber, err)
+		}
+
+		// Parts can't add up to more than total_size, even while several stream at once
+		chunkSize := int64(len(chunk.Data))
+		upload.mu.Lock()
+		// A part sent again replaces the stored one, so the old copy doesn't count
+		stored := upload.storedBytes()
+		if previous, exists := upload.parts[partNumber]; exists {
+			stored -= previous.size
+		}
+		if stored+chunkSize > upload.metadata.TotalSize {
+			upload.mu.Unlock()
+			return nil, uploadViolation(codes.OutOfRange, "chunk.data",
+				fmt.Sprintf("parts would hold %d bytes, beyond total_size %d", stored+chunkSize, upload.metadata.TotalSize))
+		}
+		upload.streaming += chunkSize
+		upload.mu.Unlock()
+		size += chunkSize
+
+		if err := s.quotas.charge(userID, chunkSize); err != nil {
+			size -= chunkSize
+			upload.mu.Lock()
+			upload.streaming -= chunkSize
+			upload.mu.Unlock()
+			return nil, err
+		}
+		if _, err := blob.Write(chunk.Data); err != nil {
+			slog.ErrorContext(ctx, "Failed to write part", "upload_id", upload.uploadID, "part_number", partNumber, "error", err)
+			return nil, status.Errorf(codes.Internal, "failed to store part: %v", err)
+		}
+		s.metrics.uploadBytesReceived.Add(chunkSize)
+		chunkCount++
+	}
+
+	if err := blob.Commit(); err != nil {
+		slog.ErrorContext(ctx, "Failed to commit part", "upload_id", upload.uploadID, "part_number", partNumber, "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to store part: %v", err)
+	}
+	committed = true
+
+	return &multipartPart{size: size, sha256: blob.Digest()}, nil
+}
+
+// CompleteUpload assembles the listed parts into the final upload
+func (s *server) CompleteUpload(ctx context.Context, req *userv1.CompleteUploadRequest) (*userv1.UploadUserDataResponse, error) {
+	if req.UploadId == "" {
+		return nil, status.Error(codes.InvalidArgument, "upload_id is required")
+	}
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if len(req.Parts) == 0 {
+		return nil, uploadViolation(codes.InvalidArgument, "parts", "at least one part is required")
+	}
+
+	upload, err := s.multipart.get(req.UploadId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	upload.mu.Lock()
+	if upload.completing {
+		upload.mu.Unlock()
+		return nil, status.Errorf(codes.FailedPrecondition, "upload %s is already being completed", req.UploadId)
+	}
+	if upload.active > 0 {
+		upload.mu.Unlock()
+		return nil, status.Errorf(codes.FailedPrecondition, "upload %s still has %d parts streaming", req.UploadId, upload.active)
+	}
+
+	parts, err := selectParts(upload, req.Parts)
+	if err != nil {
+		upload.mu.Unlock()
+		return nil, err
+	}
+	upload.completing = true
+	upload.mu.Unlock()
+
+	resp, err := s.assembleParts(ctx, upload, parts)
+	if err != nil {
+		// Leave the parts in place so the client can fix the list and retry
+		upload.mu.Lock()
+		upload.completing = false
+		upload.updatedAt = time.Now()
+		upload.mu.Unlock()
+		return nil, err
+	}
+
+	// The final blob holds its own reference, so every part can be released
+	upload.mu.Lock()
+	for _, part := range upload.parts {
+		if err := s.blobs.Release(part.sha256); err != nil {
+			slog.ErrorContext(ctx, "Failed to release part blob", "upload_id", upload.uploadID, "sha256", part.sha256, "error", err)
+		}
+	}
+	for partNumber, part := range upload.parts {
+		if !containsPart(parts, part) {
+			s.quotas.release(upload.metadata.UserId, part.size)
+		}
+		delete(upload.parts, partNumber)
+	}
+	upload.mu.Unlock()
+	s.multipart.remove(upload.uploadID)
+
+	return resp, nil
+}
+
+// selectParts checks the requested list against the stored parts; upload.mu must be held
+func selectParts(upload *multipartUpload, requested []*userv1.CompletedPart) ([]*multipartPart, error) {
+	var (
+		parts []*multipartPart
+		total int64
+		last  int32
+	)
+	for i, requestedPart := range requested {
+		field := fmt.Sprintf("parts[%d]", i)
+		if requestedPart.PartNumber <= last {
+			return nil, uploadViolation(codes.InvalidArgument, field+".part_number", "part numbers must be in ascending order")
+		}
+		last = requestedPart.PartNumber
+
+		part, exists := upload.parts[requestedPart.PartNumber]
+		if !exists {
+			return nil, uploadViolation(codes.InvalidArgument, field+".part_number",
+				fmt.Sprintf("part %d has not been uploaded", requestedPart.PartNumber))
+		}
+		if requestedPart.Sha256 != "" && requestedPart.Sha256 != part.sha256 {
+			return nil, uploadViolation(codes.InvalidArgument, field+".sha256",
+				fmt.Sprintf("part %d has sha256 %s", requestedPart.PartNumber, part.sha256))
+		}
+		parts = append(parts, part)
+		total += part.size
+	}
+
+	if total != upload.metadata.TotalSize {
+		return nil, uploadViolation(codes.OutOfRange, "parts",
+			fmt.Sprintf("parts hold %d bytes, expected total_size %d", total, upload.metadata.TotalSize))
+	}
+	return parts, nil
+}
+
+func containsPart(parts []*multipartPart, part *multipartPart) bool {
+	for _, p := range parts {
+		if p == part {
+			return true
+		}
+	}
+	return false
+}
+
+// assembleParts concatenates parts into a new blob, verifies it and records the upload
+func (s *server) assembleParts(ctx context.Context, upload *multipartUpload, parts []*multipartPart) (*userv1.UploadUserDataResponse, error) {
+	blob, err := s.blobs.Create()
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to create blob", "upload_id", upload.uploadID, "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to store upload: %v", err)
+	}
+
+	// Parts can arrive in any order, so the content is only checked once it is assembled
+	head := &headWriter{limit: sniffLength}
+	for i, part := range parts {
+		if err := copyBlob(s.blobs, part.sha256, io.MultiWriter(blob, head)); err != nil {
+			blob.Abort()
+			slog.ErrorContext(ctx, "Failed to assemble part", "upload_id", upload.uploadID, "part_number", i+1, "error", err)
+			return nil, status.Errorf(codes.Internal, "failed to assemble upload: %v", err)
+		}
+	}
+
+	computedSHA := blob.Digest()
+	if upload.expectedSHA != "" && computedSHA != upload.expectedSHA {
+		blob.Abort()
+		slog.WarnContext(ctx, "Upload is corrupt", "upload_id", upload.uploadID, "expected_sha256", upload.expectedSHA, "computed_sha256", computedSHA)
+		return nil, status.Errorf(codes.DataLoss, "sha256 mismatch: expected %s, computed %s", upload.expectedSHA, computedSHA)
+	}
+
+	var contentType string
+	if len(head.data) > 0 {
+		contentType, err = s.checkContent(upload.metadata.ContentType, upload.metadata.Filename, head.data)
+		if err != nil {
+			blob.Abort()
+			return nil, err
+		}
+	}
+
+	if err := blob.Commit(); err != nil {
+		slog.ErrorContext(ctx, "Failed to commit upload", "upload_id", upload.uploadID, "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to store upload: %v", err)
+	}
+
+	size := upload.metadata.TotalSize
+	err = s.recordUpload(ctx, &uploadRecord{
+		uploadID:    upload.uploadID,
+		userID:      upload.metadata.UserId,
+		filename:    upload.metadata.Filename,
+		contentType: resolvedContentType(upload.metadata.ContentType, contentType),
+		size:        size,
+		chunkCount:  int32(len(parts)),
+		sha256:      computedSHA,
+		createdAt:   time.Now(),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to record upload", "upload_id", upload.uploadID, "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to store upload: %v", err)
+	}
+	s.quotas.complete(upload.metadata.UserId, size)
+
+	slog.InfoContext(ctx, "Completed multipart upload", "upload_id", upload.uploadID, "bytes", size, "parts", len(parts))
+	return &userv1.UploadUserDataResponse{
+		UploadId:      upload.uploadID,
+		BytesReceived: size,
+		Success:       true,
+		Sha256:        computedSHA,
+	}, nil
+}
+
+// copyBlob appends the committed blob digest to dst
+func copyBlob(blobs blobStore, digest string, dst io.Writer) error {
+	src, err := blobs.Open(digest)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	_, err = io.Copy(dst, src)
+	return err
+}
+
+// headWriter keeps the first limit bytes written to it
+type headWriter struct {
+	data  []byte
+	limit int
+}
+
+func (h *headWriter) Write(p []byte) (int, error) {
+	if room := h.limit - len(h.data); room > 0 {
+		h.data = append(h.data, p[:min(room, len(p))]...)
+	}
+	return len(p), nil
+}
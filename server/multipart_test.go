@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	userv1 "grpc-go-learning/gen/go/user/v1/user"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testUserID = "user_1"
+
+// newTestServer returns a server with one user and uploads stored under a temporary directory
+func newTestServer(t *testing.T) *server {
+	t.Helper()
+
+	blobs, err := newLocalBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &server{
+		users:               map[string]*userv1.User{testUserID: {UserId: testUserID}},
+		blobs:               blobs,
+		uploads:             newUploadIndex(),
+		pending:             newPendingUploads(time.Hour),
+		quotas:              newQuotaTracker(defaultMaxUploadSize, defaultUserQuota),
+		multipart:           newMultipartUploads(time.Hour),
+		allowedContentTypes: defaultAllowedContentTypes,
+		processing:          newProcessingPipeline(),
+		metrics:             newServerMetrics(),
+	}
+}
+
+// fakePartStream feeds a prepared UploadPart request stream to the handler
+type fakePartStream struct {
+	grpc.ServerStream
+	requests []*userv1.UploadPartRequest
+	response *userv1.UploadPartResponse
+}
+
+func (f *fakePartStream) Context() context.Context {
+	return context.Background()
+}
+
+func (f *fakePartStream) Recv() (*userv1.UploadPartRequest, error) {
+	if len(f.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := f.requests[0]
+	f.requests = f.requests[1:]
+	return req, nil
+}
+
+func (f *fakePartStream) SendAndClose(resp *userv1.UploadPartResponse) error {
+	f.response = resp
+	return nil
+}
+
+// initiate starts a multipart upload and returns its ID
+func initiate(t *testing.T, s *server, totalSize int64, contentType string) string {
+	t.Helper()
+
+	resp, err := s.InitiateUpload(context.Background(), &userv1.InitiateUploadRequest{
+		Metadata: &userv1.UserMetadata{
+			UserId:      testUserID,
+			Filename:    "file.bin",
+			TotalSize:   totalSize,
+			ContentType: contentType,
+		},
+	})
+	if err != nil {
+		t.Fatalf("InitiateUpload: %v", err)
+	}
+	return resp.UploadId
+}
+
+// uploadPart sends data as part partNumber in a single chunk
+func uploadPart(s *server, uploadID string, partNumber int32, data []byte) error {
+	return s.UploadPart(&fakePartStream{requests: []*userv1.UploadPartRequest{
+		{Data: &userv1.UploadPartRequest_Header{Header: &userv1.UploadPartHeader{
+			UploadId:   uploadID,
+			UserId:     testUserID,
+			PartNumber: partNumber,
+		}}},
+		{Data: &userv1.UploadPartRequest_Chunk{Chunk: &userv1.UserDataChunk{Data: data}}},
+	}})
+}
+
+// complete assembles the listed parts
+func complete(s *server, uploadID string, partNumbers ...int32) (*userv1.UploadUserDataResponse, error) {
+	req := &userv1.CompleteUploadRequest{UploadId: uploadID, UserId: testUserID}
+	for _, n := range partNumbers {
+		req.Parts = append(req.Parts, &userv1.CompletedPart{PartNumber: n})
+	}
+	return s.CompleteUpload(context.Background(), req)
+}
+
+func TestUploadPartReplacesPart(t *testing.T) {
+	s := newTestServer(t)
+	uploadID := initiate(t, s, 10, "text/plain")
+
+	for _, step := range []struct {
+		part int32
+		data string
+	}{
+		{1, "hello"},
+		{2, "xxxxx"},
+		{2, "world"}, // replaces part 2 within total_size
+	} {
+		if err := uploadPart(s, uploadID, step.part, []byte(step.data)); err != nil {
+			t.Fatalf("UploadPart %d %q: %v", step.part, step.data, err)
+		}
+	}
+	if got := s.quotas.snapshot(testUserID).inProgress; got != 10 {
+		t.Fatalf("in-progress bytes after replacing a part = %d, want 10", got)
+	}
+
+	// A replacement still can't grow the parts beyond total_size
+	err := uploadPart(s, uploadID, 2, []byte("world!"))
+	if status.Code(err) != codes.OutOfRange {
+		t.Fatalf("oversized replacement: got %v, want OutOfRange", err)
+	}
+
+	resp, err := complete(s, uploadID, 1, 2)
+	if err != nil {
+		t.Fatalf("CompleteUpload: %v", err)
+	}
+	if got := readUpload(t, s, resp.UploadId); got != "helloworld" {
+		t.Fatalf("assembled upload = %q, want %q", got, "helloworld")
+	}
+}
+
+// readUpload returns the stored content of a completed upload
+func readUpload(t *testing.T, s *server, uploadID string) string {
+	t.Helper()
+
+	record, ok := s.uploads.get(uploadID)
+	if !ok {
+		t.Fatalf("upload %s not recorded", uploadID)
+	}
+	blob, err := s.blobs.Open(record.sha256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer blob.Close()
+	data, err := io.ReadAll(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestCompleteUploadChecksAssembledContent(t *testing.T) {
+	pdf := []byte("%PDF-1.7\n1 0 obj\n")
+	gzip := []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\x03")
+	text := []byte("plain text, nothing to see here\n")
+
+	type part struct {
+		number int32
+		data   []byte
+	}
+	tests := []struct {
+		name        string
+		contentType string
+		parts       []part // in upload order
+		complete    []int32
+		wantErr     bool
+		wantType    string
+	}{
+		{
+			name:        "pdf declared as text without part 1",
+			contentType: "text/plain",
+			parts:       []part{{2, pdf}, {3, text}},
+			complete:    []int32{2, 3},
+			wantErr:     true,
+		},
+		{
+			name:        "pdf declared as text with part 1 sent last",
+			contentType: "text/plain",
+			parts:       []part{{2, text}, {1, pdf}},
+			complete:    []int32{1, 2},
+			wantErr:     true,
+		},
+		{
+			name:     "undeclared gzip is not an allowed type",
+			parts:    []part{{2, text}, {1, gzip}},
+			complete: []int32{1, 2},
+			wantErr:  true,
+		},
+		{
+			name:        "declared pdf with part 1 sent last",
+			contentType: "application/pdf",
+			parts:       []part{{2, text}, {1, pdf}},
+			complete:    []int32{1, 2},
+			wantType:    "application/pdf",
+		},
+		{
+			name:     "undeclared text is recorded as sniffed",
+			parts:    []part{{3, text}, {2, text}},
+			complete: []int32{2, 3},
+			wantType: "text/plain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+
+			var totalSize int64
+			for _, p := range tt.parts {
+				totalSize += int64(len(p.data))
+			}
+			uploadID := initiate(t, s, totalSize, tt.contentType)
+			for _, p := range tt.parts {
+				if err := uploadPart(s, uploadID, p.number, p.data); err != nil {
+					t.Fatalf("UploadPart %d: %v", p.number, err)
+				}
+			}
+
+			_, err := complete(s, uploadID, tt.complete...)
+			if tt.wantErr {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("CompleteUpload: got %v, want InvalidArgument", err)
+				}
+				if _, ok := s.uploads.get(uploadID); ok {
+					t.Fatal("rejected upload was recorded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CompleteUpload: %v", err)
+			}
+			record, _ := s.uploads.get(uploadID)
+			if record.contentType != tt.wantType {
+				t.Fatalf("recorded content type %q, want %q", record.contentType, tt.wantType)
+			}
+		})
+	}
+}
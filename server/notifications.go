@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	userv1 "grpc-go-learning/gen/go/user/v1/user"
+)
+
+// defaultDedupWindow is how long a dedup_key is remembered per user
+const defaultDedupWindow = 10 * time.Minute
+
+// dedupGCInterval is how often dedup keys past their window are forgotten
+const dedupGCInterval = time.Minute
+
+// subscriberBuffer is the number of notifications queued per subscriber
+const subscriberBuffer = 16
+
+// dedupEntry remembers the notification that first used a dedup key
+type dedupEntry struct {
+	notificationID string
+	expiresAt      time.Time
+}
+
+// publishResult is the outcome of publishing one notification
+type publishResult struct {
+	notificationID string // the original notification's ID when deduplicated
+	deduplicated   bool   // dropped as a repeat of an earlier dedup_key
+	delivered      int    // subscribers the notification was queued for
+}
+
+// notificationHub fans published notifications out to StreamNotifications subscribers
+type notificationHub struct {
+	mu          sync.Mutex
+	dedupWindow time.Duration
+	subscribers map[string]map[chan *userv1.Notification]struct{} // user_id -> subscriber channels
+	seen        map[string]dedupEntry                             // user_id + dedup_key -> first delivery
+	nextID      int
+	closed      bool // set at shutdown; subscriber channels are closed
+}
+
+func newNotificationHub(dedupWindow time.Duration) *notificationHub {
+	return &notificationHub{
+		dedupWindow: dedupWindow,
+		subscribers: make(map[string]map[chan *userv1.Notification]struct{}),
+		seen:        make(map[string]dedupEntry),
+	}
+}
+
+// subscribe registers a subscriber for userID; call the returned func to unsubscribe.
+// The channel is closed when the hub shuts down.
+func (h *notificationHub) subscribe(userID string) (<-chan *userv1.Notification, func()) {
+	ch := make(chan *userv1.Notification, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		close(ch)
+		return ch, func() {}
+	}
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[chan *userv1.Notification]struct{})
+	}
+	h.subscribers[userID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subscribers[userID], ch)
+		if len(h.subscribers[userID]) == 0 {
+			delete(h.subscribers, userID)
+		}
+	}
+}
+
+// publish delivers n to the subscribers of n.UserId. A subscriber whose buffer is
+// full misses it, and isn't counted as delivered.
+func (h *notificationHub) publish(n *userv1.Notification) publishResult {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	key := n.UserId + "\x00" + n.DedupKey
+	if n.DedupKey != "" {
+		// Expired keys stay in seen until the collector runs
+		if entry, ok := h.seen[key]; ok && !now.After(entry.expiresAt) {
+			return publishResult{notificationID: entry.notificationID, deduplicated: true}
+		}
+	}
+
+	h.nextID++
+	n.NotificationId = fmt.Sprintf("notif_%d", h.nextID)
+	if n.Timestamp == 0 {
+		n.Timestamp = now.Unix()
+	}
+	if n.DedupKey != "" {
+		h.seen[key] = dedupEntry{notificationID: n.NotificationId, expiresAt: now.Add(h.dedupWindow)}
+	}
+
+	result := publishResult{notificationID: n.NotificationId}
+	for ch := range h.subscribers[n.UserId] {
+		select {
+		case ch <- n:
+			result.delivered++
+		default:
+			slog.Warn("Subscriber is full, dropping notification", "user_id", n.UserId, "notification_id", n.NotificationId)
+		}
+	}
+	return result
+}
+
+// subscriberCount returns the number of open subscriptions
+func (h *notificationHub) subscriberCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	n := 0
+	for _, subscribers := range h.subscribers {
+		n += len(subscribers)
+	}
+	return n
+}
+
+// close ends every subscription so StreamNotifications can return cleanly
+func (h *notificationHub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for userID, subscribers := range h.subscribers {
+		for ch := range subscribers {
+			close(ch)
+		}
+		delete(h.subscribers, userID)
+	}
+}
+
+// collectExpired forgets dedup keys whose window has passed
+func (h *notificationHub) collectExpired(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for key, entry := range h.seen {
+		if now.After(entry.expiresAt) {
+			delete(h.seen, key)
+		}
+	}
+}
+
+// runCollector calls collectExpired every interval, forever
+func (h *notificationHub) runCollector(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		h.collectExpired(now)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	userv1 "grpc-go-learning/gen/go/user/v1/user"
+)
+
+func TestNotificationHubDedupWindow(t *testing.T) {
+	h := newNotificationHub(time.Hour)
+
+	first := h.publish(&userv1.Notification{UserId: "user_a", DedupKey: "k"})
+	repeat := h.publish(&userv1.Notification{UserId: "user_a", DedupKey: "k"})
+	if !repeat.deduplicated || repeat.notificationID != first.notificationID {
+		t.Fatalf("repeat = %+v, want a duplicate of %s", repeat, first.notificationID)
+	}
+	if other := h.publish(&userv1.Notification{UserId: "user_b", DedupKey: "k"}); other.deduplicated {
+		t.Fatal("dedup key shared across users")
+	}
+
+	// Past the window a key no longer matches, even before it is collected
+	h.seen["user_a\x00k"] = dedupEntry{notificationID: first.notificationID, expiresAt: time.Now().Add(-time.Second)}
+	if again := h.publish(&userv1.Notification{UserId: "user_a", DedupKey: "k"}); again.deduplicated {
+		t.Fatal("expired dedup key still matched")
+	}
+
+	h.seen["user_a\x00k"] = dedupEntry{expiresAt: time.Now().Add(-time.Second)}
+	h.collectExpired(time.Now())
+	if _, ok := h.seen["user_a\x00k"]; ok {
+		t.Fatal("expired dedup key not collected")
+	}
+	if _, ok := h.seen["user_b\x00k"]; !ok {
+		t.Fatal("live dedup key collected")
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultIncompleteUploadTTL is how long an idle incomplete upload can wait to be resumed
+	defaultIncompleteUploadTTL = time.Hour
+	// uploadGCInterval is how often expired incomplete uploads are collected
+	uploadGCInterval = time.Minute
+)
+
+// pendingUploads holds incomplete uploads so they can be resumed by a later stream
+type pendingUploads struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	sessions map[string]*uploadSession // upload_id -> session
+}
+
+func newPendingUploads(ttl time.Duration) *pendingUploads {
+	return &pendingUploads{
+		ttl:      ttl,
+		sessions: make(map[string]*uploadSession),
+	}
+}
+
+// add registers a new session that is attached to a stream
+func (p *pendingUploads) add(session *uploadSession) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sessions[session.uploadID] = session
+}
+
+// claim attaches a parked session to the calling stream
+func (p *pendingUploads) claim(uploadID, userID string) (*uploadSession, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	session, exists := p.sessions[uploadID]
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "incomplete upload %s not found", uploadID)
+	}
+	if session.metadata.UserId != userID {
+		return nil, status.Errorf(codes.PermissionDenied, "upload %s belongs to another user", uploadID)
+	}
+	if session.state != uploadParked {
+		return nil, status.Errorf(codes.FailedPrecondition, "upload %s is in use by another stream", uploadID)
+	}
+
+	session.state = uploadReceiving
+	return session, nil
+}
+
+// release detaches session from its stream, parking it for resumption unless it is finished
+func (p *pendingUploads) release(ctx context.Context, session *uploadSession) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch session.state {
+	case uploadReceiving:
+		session.state = uploadParked
+		session.parkedAt = time.Now()
+		slog.InfoContext(ctx, "Upload parked until resumed or expired", "upload_id", session.uploadID)
+	case uploadCompleted, uploadFailed:
+		delete(p.sessions, session.uploadID)
+	}
+}
+
+// mark records the outcome of a session; session.mu may be held, but not p.mu
+func (p *pendingUploads) mark(session *uploadSession, state uploadState) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	session.state = state
+}
+
+// get returns the session for uploadID and when it expires (zero while attached)
+func (p *pendingUploads) get(uploadID string) (*uploadSession, time.Time, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	session, exists := p.sessions[uploadID]
+	if !exists {
+		return nil, time.Time{}, false
+	}
+	var expiresAt time.Time
+	if session.state == uploadParked {
+		expiresAt = session.parkedAt.Add(p.ttl)
+	}
+	return session, expiresAt, true
+}
+
+// collectExpired discards parked sessions that have been idle longer than the TTL
+func (p *pendingUploads) collectExpired(now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for uploadID, session := range p.sessions {
+		if session.state == uploadParked && now.Sub(session.parkedAt) > p.ttl {
+			session.blob.Abort()
+			session.server.quotas.release(session.metadata.UserId, session.bytesReceived)
+			delete(p.sessions, uploadID)
+			slog.Info("Discarded expired incomplete upload", "upload_id", uploadID)
+		}
+	}
+}
+
+// runCollector calls collectExpired every interval, forever
+func (p *pendingUploads) runCollector(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		p.collectExpired(now)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	userv1 "grpc-go-learning/gen/go/user/v1/user"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeUploadStream feeds prepared UploadUserData requests to the handler. With drop
+// set, the client goes away after the last request instead of closing the stream.
+type fakeUploadStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	requests []*userv1.UploadUserDataRequest
+	drop     context.CancelFunc
+	header   metadata.MD
+	response *userv1.UploadUserDataResponse
+}
+
+func (f *fakeUploadStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeUploadStream) Recv() (*userv1.UploadUserDataRequest, error) {
+	if len(f.requests) == 0 {
+		if f.drop != nil {
+			f.drop()
+			return nil, status.Error(codes.Canceled, "context canceled")
+		}
+		return nil, io.EOF
+	}
+	req := f.requests[0]
+	f.requests = f.requests[1:]
+	return req, nil
+}
+
+func (f *fakeUploadStream) SendHeader(md metadata.MD) error {
+	f.header = md
+	return nil
+}
+
+func (f *fakeUploadStream) SendAndClose(resp *userv1.UploadUserDataResponse) error {
+	f.response = resp
+	return nil
+}
+
+// sendUpload runs UploadUserData with metadata followed by chunks numbered from firstChunk
+func sendUpload(s *server, meta *userv1.UserMetadata, firstChunk int32, drop bool, chunks ...string) (*fakeUploadStream, error) {
+	stream := &fakeUploadStream{ctx: context.Background()}
+	if drop {
+		stream.ctx, stream.drop = context.WithCancel(stream.ctx)
+	}
+	stream.requests = append(stream.requests, &userv1.UploadUserDataRequest{
+		Data: &userv1.UploadUserDataRequest_Metadata{Metadata: meta},
+	})
+	for i, data := range chunks {
+		stream.requests = append(stream.requests, &userv1.UploadUserDataRequest{
+			Data: &userv1.UploadUserDataRequest_Chunk{Chunk: &userv1.UserDataChunk{Data: []byte(data), ChunkNumber: firstChunk + int32(i)}},
+		})
+	}
+	return stream, s.UploadUserData(stream)
+}
+
+// startParkedUpload sends the first chunk of a 10 byte upload and drops the stream
+func startParkedUpload(t *testing.T, s *server) string {
+	t.Helper()
+
+	stream, err := sendUpload(s, &userv1.UserMetadata{UserId: testUserID, Filename: "notes.txt", TotalSize: 10}, 0, true, "hello")
+	if status.Code(err) != codes.Canceled {
+		t.Fatalf("dropped upload: got %v, want Canceled", err)
+	}
+	return stream.header.Get(uploadIDHeader)[0]
+}
+
+func resumeMetadata(uploadID string) *userv1.UserMetadata {
+	return &userv1.UserMetadata{UserId: testUserID, Filename: "notes.txt", TotalSize: 10, ResumeUploadId: uploadID}
+}
+
+func TestResumeUploadAtCommittedChunk(t *testing.T) {
+	s := newTestServer(t)
+	uploadID := startParkedUpload(t, s)
+
+	st, err := s.GetUploadStatus(context.Background(), &userv1.GetUploadStatusRequest{UploadId: uploadID, UserId: testUserID})
+	if err != nil {
+		t.Fatalf("GetUploadStatus: %v", err)
+	}
+	if st.State != userv1.UploadState_UPLOAD_STATE_IN_PROGRESS || st.CommittedBytes != 5 || st.ChunkCount != 1 {
+		t.Fatalf("status = %v, %d bytes, %d chunks; want in progress, 5 bytes, 1 chunk", st.State, st.CommittedBytes, st.ChunkCount)
+	}
+
+	// Starting over at chunk 0 is rejected and leaves the session resumable
+	if _, err := sendUpload(s, resumeMetadata(uploadID), 0, false, "hello"); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("resume from chunk 0: got %v, want InvalidArgument", err)
+	}
+
+	stream, err := sendUpload(s, resumeMetadata(uploadID), st.ChunkCount, false, "world")
+	if err != nil {
+		t.Fatalf("resume from chunk %d: %v", st.ChunkCount, err)
+	}
+	if stream.response.UploadId != uploadID || stream.response.BytesReceived != 10 {
+		t.Fatalf("response = %v, want all 10 bytes of %s", stream.response, uploadID)
+	}
+	if got := readUpload(t, s, uploadID); got != "helloworld" {
+		t.Fatalf("stored upload = %q, want %q", got, "helloworld")
+	}
+	if got := s.quotas.snapshot(testUserID); got != (userUsage{used: 10}) {
+		t.Fatalf("usage = %+v, want 10 used", got)
+	}
+}
+
+func TestResumeUploadRejections(t *testing.T) {
+	s := newTestServer(t)
+	s.users["user_2"] = &userv1.User{UserId: "user_2"}
+	uploadID := startParkedUpload(t, s)
+
+	foreign := resumeMetadata(uploadID)
+	foreign.UserId = "user_2"
+	resized := resumeMetadata(uploadID)
+	resized.TotalSize = 11
+
+	tests := []struct {
+		name string
+		meta *userv1.UserMetadata
+		want codes.Code
+	}{
+		{name: "unknown upload", meta: resumeMetadata("upload_missing"), want: codes.NotFound},
+		{name: "another user's upload", meta: foreign, want: codes.PermissionDenied},
+		{name: "different total_size", meta: resized, want: codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		if _, err := sendUpload(s, tt.meta, 1, false, "world"); status.Code(err) != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	// None of the rejections used up the session
+	if _, err := sendUpload(s, resumeMetadata(uploadID), 1, false, "world"); err != nil {
+		t.Fatalf("resume after rejections: %v", err)
+	}
+	// and a completed upload can't be resumed again
+	if _, err := sendUpload(s, resumeMetadata(uploadID), 2, false, "!"); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("resume completed upload: got %v, want FailedPrecondition", err)
+	}
+}
+
+func TestExpiredUploadIsCollected(t *testing.T) {
+	s := newTestServer(t)
+	uploadID := startParkedUpload(t, s)
+
+	// Not yet past the TTL
+	s.pending.collectExpired(time.Now())
+	if _, _, ok := s.pending.get(uploadID); !ok {
+		t.Fatal("session collected before its TTL")
+	}
+
+	s.pending.collectExpired(time.Now().Add(s.pending.ttl + time.Second))
+
+	_, err := s.GetUploadStatus(context.Background(), &userv1.GetUploadStatusRequest{UploadId: uploadID, UserId: testUserID})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("GetUploadStatus after expiry: got %v, want NotFound", err)
+	}
+	if _, err := sendUpload(s, resumeMetadata(uploadID), 1, false, "world"); status.Code(err) != codes.NotFound {
+		t.Fatalf("resume after expiry: got %v, want NotFound", err)
+	}
+	if got := s.quotas.snapshot(testUserID); got != (userUsage{}) {
+		t.Fatalf("usage after expiry = %+v, want the bytes returned", got)
+	}
+}
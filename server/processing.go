@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	userv1 "grpc-go-learning/gen/go/user/v1/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// processingTimeout bounds the run of all processors on one upload
+const processingTimeout = 30 * time.Second
+
+// errNotApplicable is returned by a processor that doesn't handle an upload of its content type
+var errNotApplicable = errors.New("processor does not apply to this upload")
+
+// uploadProcessor acts on the data of a completed upload
+type uploadProcessor interface {
+	// Name identifies the processor in ProcessingStatus results
+	Name() string
+	// Process reads the upload's data; returning errNotApplicable marks it skipped
+	Process(ctx context.Context, upload *uploadRecord, data io.Reader) error
+}
+
+// processingPipeline runs the processors registered for an upload's content type
+type processingPipeline struct {
+	processors map[string][]uploadProcessor // content type -> processors, run in order
+
+	mu       sync.Mutex
+	statuses map[string]*userv1.ProcessingStatus // upload_id -> status
+}
+
+func newProcessingPipeline() *processingPipeline {
+	return &processingPipeline{
+		processors: make(map[string][]uploadProcessor),
+		statuses:   make(map[string]*userv1.ProcessingStatus),
+	}
+}
+
+// register adds p for uploads of contentType; call before serving
+func (p *processingPipeline) register(contentType string, processor uploadProcessor) {
+	p.processors[contentType] = append(p.processors[contentType], processor)
+}
+
+// submit records upload as pending and processes it in the background, keeping the
+// request and trace IDs of ctx but not its cancellation
+func (p *processingPipeline) submit(ctx context.Context, blobs blobStore, upload *uploadRecord) {
+	processors := p.processors[upload.contentType]
+
+	st := &userv1.ProcessingStatus{
+		UploadId:  upload.uploadID,
+		State:     userv1.ProcessingState_PROCESSING_STATE_PENDING,
+		UpdatedAt: time.Now().Unix(),
+	}
+	if len(processors) == 0 {
+		st.State = userv1.ProcessingState_PROCESSING_STATE_SKIPPED
+	}
+
+	p.mu.Lock()
+	p.statuses[upload.uploadID] = st
+	p.mu.Unlock()
+
+	if len(processors) > 0 {
+		go p.run(context.WithoutCancel(ctx), blobs, upload, processors)
+	}
+}
+
+// run executes processors one after another, each reading the upload from the start
+func (p *processingPipeline) run(ctx context.Context, blobs blobStore, upload *uploadRecord, processors []uploadProcessor) {
+	ctx, cancel := context.WithTimeout(ctx, processingTimeout)
+	defer cancel()
+
+	p.update(upload.uploadID, func(st *userv1.ProcessingStatus) {
+		st.State = userv1.ProcessingState_PROCESSING_STATE_RUNNING
+	})
+
+	overall := userv1.ProcessingState_PROCESSING_STATE_SKIPPED
+	for _, processor := range processors {
+		result := &userv1.ProcessorResult{Processor: processor.Name()}
+
+		err := runProcessor(ctx, blobs, upload, processor)
+		switch {
+		case errors.Is(err, errNotApplicable):
+			result.State = userv1.ProcessingState_PROCESSING_STATE_SKIPPED
+		case err != nil:
+			slog.WarnContext(ctx, "Processor failed", "processor", processor.Name(), "upload_id", upload.uploadID, "error", err)
+			result.State = userv1.ProcessingState_PROCESSING_STATE_FAILED
+			result.Error = err.Error()
+			overall = userv1.ProcessingState_PROCESSING_STATE_FAILED
+		default:
+			slog.InfoContext(ctx, "Processor finished", "processor", processor.Name(), "upload_id", upload.uploadID)
+			result.State = userv1.ProcessingState_PROCESSING_STATE_SUCCEEDED
+			if overall != userv1.ProcessingState_PROCESSING_STATE_FAILED {
+				overall = userv1.ProcessingState_PROCESSING_STATE_SUCCEEDED
+			}
+		}
+
+		p.update(upload.uploadID, func(st *userv1.ProcessingStatus) {
+			st.Results = append(st.Results, result)
+		})
+	}
+
+	p.update(upload.uploadID, func(st *userv1.ProcessingStatus) {
+		st.State = overall
+	})
+}
+
+// runProcessor gives processor a fresh reader over the upload's data
+func runProcessor(ctx context.Context, blobs blobStore, upload *uploadRecord, processor uploadProcessor) error {
+	data, err := blobs.Open(upload.sha256)
+	if err != nil {
+		return err
+	}
+	defer data.Close()
+	return processor.Process(ctx, upload, data)
+}
+
+func (p *processingPipeline) update(uploadID string, change func(*userv1.ProcessingStatus)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// The upload may have been deleted while processing
+	st, ok := p.statuses[uploadID]
+	if !ok {
+		return
+	}
+	change(st)
+	st.UpdatedAt = time.Now().Unix()
+}
+
+// forget drops the status of a deleted upload
+func (p *processingPipeline) forget(uploadID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.statuses, uploadID)
+}
+
+// get returns a copy of the status so callers can read it without the lock
+func (p *processingPipeline) get(uploadID string) (*userv1.ProcessingStatus, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	st, ok := p.statuses[uploadID]
+	if !ok {
+		return nil, false
+	}
+	return proto.Clone(st).(*userv1.ProcessingStatus), true
+}
+
+// recordUpload makes a committed upload visible and hands it to the processing pipeline.
+// When the record can't be stored the upload's reference to its data is released.
+func (s *server) recordUpload(ctx context.Context, record *uploadRecord) error {
+	if err := s.uploads.add(record); err != nil {
+		if releaseErr := s.blobs.Release(record.sha256); releaseErr != nil {
+			err = errors.Join(err, releaseErr)
+		}
+		return err
+	}
+	s.metrics.uploadBytesStored.Add(record.size)
+	s.processing.submit(ctx, s.blobs, record)
+	return nil
+}
+
+// GetProcessingStatus reports the post-upload processing of an upload
+func (s *server) GetProcessingStatus(ctx context.Context, req *userv1.GetProcessingStatusRequest) (*userv1.ProcessingStatus, error) {
+	if req.UploadId == "" {
+		return nil, status.Error(codes.InvalidArgument, "upload_id is required")
+	}
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	record, exists := s.uploads.get(req.UploadId)
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "upload %s not found", req.UploadId)
+	}
+	if record.userID != req.UserId {
+		return nil, status.Errorf(codes.PermissionDenied, "upload %s belongs to another user", req.UploadId)
+	}
+
+	st, exists := s.processing.get(req.UploadId)
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "no processing recorded for upload %s", req.UploadId)
+	}
+	return st, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"grpc-go-learning/internal/requestid"
+)
+
+// contextProcessor reports the request ID and state of the context it was run with
+type contextProcessor struct {
+	seen chan processorContext
+}
+
+type processorContext struct {
+	requestID string
+	err       error
+}
+
+func (c contextProcessor) Name() string {
+	return "context"
+}
+
+func (c contextProcessor) Process(ctx context.Context, upload *uploadRecord, data io.Reader) error {
+	c.seen <- processorContext{requestID: requestid.FromContext(ctx), err: ctx.Err()}
+	return nil
+}
+
+func TestProcessingOutlivesTheRequest(t *testing.T) {
+	s := newTestServer(t)
+	processor := contextProcessor{seen: make(chan processorContext, 1)}
+	s.processing.register("text/plain", processor)
+
+	w, err := s.blobs.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("hello"))
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The upload RPC has returned by the time processing runs
+	ctx, cancel := context.WithCancel(requestid.NewContext(context.Background(), "req_1"))
+	cancel()
+	record := &uploadRecord{uploadID: "upload_1", userID: testUserID, contentType: "text/plain", size: 5, sha256: w.Digest()}
+	if err := s.recordUpload(ctx, record); err != nil {
+		t.Fatalf("recordUpload: %v", err)
+	}
+
+	got := <-processor.seen
+	if got.err != nil {
+		t.Fatalf("processor context already done: %v", got.err)
+	}
+	if got.requestID != "req_1" {
+		t.Fatalf("processor request id = %q, want req_1", got.requestID)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+
+	userv1 "grpc-go-learning/gen/go/user/v1/user"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// profileImportFilename is the upload name the profile importer acts on
+const profileImportFilename = "user_data.json"
+
+// maxProfileImportSize bounds how much of the upload is parsed
+const maxProfileImportSize = 1 << 20 // 1MB
+
+// profileImport is the accepted shape of user_data.json; absent fields are left unchanged
+type profileImport struct {
+	Name  *string `json:"name"`
+	Email *string `json:"email"`
+	Age   *int32  `json:"age"`
+}
+
+// profileImporter merges an uploaded user_data.json into the uploader's profile
+type profileImporter struct {
+	server *server
+}
+
+func newProfileImporter(s *server) *profileImporter {
+	return &profileImporter{server: s}
+}
+
+func (p *profileImporter) Name() string {
+	return "profile-import"
+}
+
+func (p *profileImporter) Process(ctx context.Context, upload *uploadRecord, data io.Reader) error {
+	if upload.filename != profileImportFilename {
+		return errNotApplicable
+	}
+
+	var profile profileImport
+	decoder := json.NewDecoder(contextReader{ctx: ctx, r: io.LimitReader(data, maxProfileImportSize)})
+	if err := decoder.Decode(&profile); err != nil {
+		return fmt.Errorf("parse %s: %w", profileImportFilename, err)
+	}
+
+	if profile.Name != nil && *profile.Name == "" {
+		return fmt.Errorf("name must not be empty")
+	}
+	if profile.Email != nil && *profile.Email == "" {
+		return fmt.Errorf("email must not be empty")
+	}
+	if profile.Age != nil && (*profile.Age < 0 || *profile.Age > 150) {
+		return fmt.Errorf("age %d is out of range", *profile.Age)
+	}
+
+	// Don't apply a profile after the pipeline has given up on the upload
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s := p.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, exists := s.users[upload.userID]
+	if !exists {
+		return fmt.Errorf("user %s no longer exists", upload.userID)
+	}
+
+	// Replace rather than mutate, GetUser may be serializing the current value
+	merged := proto.Clone(user).(*userv1.User)
+	if profile.Name != nil {
+		merged.Name = *profile.Name
+	}
+	if profile.Email != nil {
+		merged.Email = *profile.Email
+	}
+	if profile.Age != nil {
+		merged.Age = *profile.Age
+	}
+	s.users[upload.userID] = merged
+
+	slog.InfoContext(ctx, "Imported profile", "user_id", upload.userID, "upload_id", upload.uploadID)
+	return nil
+}
+
+// contextReader stops reading once ctx is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestProfileImporterStopsAfterDeadline(t *testing.T) {
+	s := newTestServer(t)
+	upload := &uploadRecord{uploadID: "up_1", userID: testUserID, filename: profileImportFilename}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := newProfileImporter(s).Process(ctx, upload, strings.NewReader(`{"name":"Ruffy"}`))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Process with a done context = %v, want context.Canceled", err)
+	}
+	if got := s.users[testUserID].Name; got != "" {
+		t.Fatalf("profile name = %q after a cancelled import, want it unchanged", got)
+	}
+
+	if err := newProfileImporter(s).Process(context.Background(), upload, strings.NewReader(`{"name":"Ruffy"}`)); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if got := s.users[testUserID].Name; got != "Ruffy" {
+		t.Fatalf("profile name = %q, want %q", got, "Ruffy")
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	userv1 "grpc-go-learning/gen/go/user/v1/user"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultMaxUploadSize is the largest total_size accepted for one upload
+	defaultMaxUploadSize = 100 << 20 // 100MB
+	// defaultUserQuota is the storage each user may fill with uploads
+	defaultUserQuota = 1 << 30 // 1GB
+)
+
+// userUsage is the storage charged to one user
+type userUsage struct {
+	used       int64 // completed uploads
+	inProgress int64 // bytes received by incomplete uploads
+}
+
+// quotaTracker enforces the per-upload limit and per-user storage quota
+type quotaTracker struct {
+	mu            sync.Mutex
+	maxUploadSize int64
+	userQuota     int64
+	usage         map[string]*userUsage // user_id -> usage
+}
+
+func newQuotaTracker(maxUploadSize, userQuota int64) *quotaTracker {
+	return &quotaTracker{
+		maxUploadSize: maxUploadSize,
+		userQuota:     userQuota,
+		usage:         make(map[string]*userUsage),
+	}
+}
+
+// usageLocked returns the usage entry for userID, creating it; q.mu must be held
+func (q *quotaTracker) usageLocked(userID string) *userUsage {
+	usage, ok := q.usage[userID]
+	if !ok {
+		usage = &userUsage{}
+		q.usage[userID] = usage
+	}
+	return usage
+}
+
+// admit checks a declared total_size before any data is accepted
+func (q *quotaTracker) admit(userID string, totalSize int64) error {
+	if totalSize > q.maxUploadSize {
+		return quotaExceeded("upload",
+			fmt.Sprintf("total_size %d exceeds the maximum upload size of %d bytes", totalSize, q.maxUploadSize))
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	usage := q.usageLocked(userID)
+	if usage.used+usage.inProgress+totalSize > q.userQuota {
+		return quotaExceeded("user:"+userID,
+			fmt.Sprintf("upload of %d bytes would exceed the storage quota: %d of %d bytes used, %d in progress",
+				totalSize, usage.used, q.userQuota, usage.inProgress))
+	}
+	return nil
+}
+
+// charge counts n more received bytes against userID, failing if the quota would be exceeded
+func (q *quotaTracker) charge(userID string, n int64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	usage := q.usageLocked(userID)
+	if usage.used+usage.inProgress+n > q.userQuota {
+		return quotaExceeded("user:"+userID,
+			fmt.Sprintf("storage quota of %d bytes exhausted: %d used, %d in progress",
+				q.userQuota, usage.used, usage.inProgress))
+	}
+	usage.inProgress += n
+	return nil
+}
+
+// release returns n in-progress bytes when an incomplete upload is discarded
+func (q *quotaTracker) release(userID string, n int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.usageLocked(userID).inProgress -= n
+}
+
+// complete moves n bytes of a finished upload from in progress to used
+func (q *quotaTracker) complete(userID string, n int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	usage := q.usageLocked(userID)
+	usage.inProgress -= n
+	usage.used += n
+}
+
+// restore counts n bytes of an upload stored before a restart as used
+func (q *quotaTracker) restore(userID string, n int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.usageLocked(userID).used += n
+}
+
+// free returns n bytes of a deleted upload to userID's quota
+func (q *quotaTracker) free(userID string, n int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.usageLocked(userID).used -= n
+}
+
+// snapshot returns the usage of userID
+func (q *quotaTracker) snapshot(userID string) userUsage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if usage, ok := q.usage[userID]; ok {
+		return *usage
+	}
+	return userUsage{}
+}
+
+// quotaExceeded builds a ResourceExhausted error with a QuotaFailure detail
+func quotaExceeded(subject, description string) error {
+	st := status.New(codes.ResourceExhausted, description)
+	detailed, err := st.WithDetails(&errdetails.QuotaFailure{
+		Violations: []*errdetails.QuotaFailure_Violation{
+			{Subject: subject, Description: description},
+		},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// GetUserQuota reports a user's storage quota and usage
+func (s *server) GetUserQuota(ctx context.Context, req *userv1.GetUserQuotaRequest) (*userv1.GetUserQuotaResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	usage := s.quotas.snapshot(req.UserId)
+	return &userv1.GetUserQuotaResponse{
+		UserId:          req.UserId,
+		QuotaBytes:      s.quotas.userQuota,
+		UsedBytes:       usage.used,
+		InProgressBytes: usage.inProgress,
+		MaxUploadSize:   s.quotas.maxUploadSize,
+	}, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestQuotaAdmit(t *testing.T) {
+	tests := []struct {
+		name       string
+		used       int64
+		inProgress int64
+		totalSize  int64
+		wantErr    bool
+		subject    string
+	}{
+		{name: "fits", totalSize: 100},
+		{name: "exactly max upload size", totalSize: 500},
+		{name: "over max upload size", totalSize: 501, wantErr: true, subject: "upload"},
+		{name: "exactly fills quota", used: 600, inProgress: 100, totalSize: 300},
+		{name: "over quota with used bytes", used: 800, totalSize: 201, wantErr: true, subject: "user:u1"},
+		{name: "over quota with in-progress bytes", inProgress: 800, totalSize: 201, wantErr: true, subject: "user:u1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newQuotaTracker(500, 1000)
+			q.usageLocked("u1").used = tt.used
+			q.usageLocked("u1").inProgress = tt.inProgress
+
+			err := q.admit("u1", tt.totalSize)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("admit(%d) = %v, want nil", tt.totalSize, err)
+				}
+				return
+			}
+			checkQuotaFailure(t, err, tt.subject)
+		})
+	}
+}
+
+func TestQuotaChargeReleaseComplete(t *testing.T) {
+	q := newQuotaTracker(500, 1000)
+
+	if err := q.charge("u1", 400); err != nil {
+		t.Fatalf("charge(400) = %v", err)
+	}
+	if err := q.charge("u1", 400); err != nil {
+		t.Fatalf("second charge(400) = %v", err)
+	}
+	checkQuotaFailure(t, q.charge("u1", 201), "user:u1")
+	if got := q.snapshot("u1"); got != (userUsage{inProgress: 800}) {
+		t.Fatalf("after a rejected charge usage = %+v, want 800 in progress", got)
+	}
+
+	// Discarding one upload frees its bytes; completing the other moves them to used
+	q.release("u1", 400)
+	q.complete("u1", 400)
+	if got := q.snapshot("u1"); got != (userUsage{used: 400}) {
+		t.Fatalf("after release and complete usage = %+v, want 400 used", got)
+	}
+	if err := q.charge("u1", 600); err != nil {
+		t.Fatalf("charge(600) after release = %v", err)
+	}
+
+	q.release("u1", 600)
+	q.free("u1", 400)
+	if got := q.snapshot("u1"); got != (userUsage{}) {
+		t.Fatalf("after freeing everything usage = %+v, want zero", got)
+	}
+
+	// Users are charged independently
+	if err := q.charge("u2", 1000); err != nil {
+		t.Fatalf("charge for another user = %v", err)
+	}
+}
+
+// checkQuotaFailure asserts err is ResourceExhausted with a QuotaFailure for subject
+func checkQuotaFailure(t *testing.T, err error, subject string) {
+	t.Helper()
+
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("code = %v, want ResourceExhausted (err %v)", st.Code(), err)
+	}
+	for _, detail := range st.Details() {
+		if failure, ok := detail.(*errdetails.QuotaFailure); ok {
+			if got := failure.Violations[0].Subject; got != subject {
+				t.Fatalf("violation subject = %q, want %q", got, subject)
+			}
+			return
+		}
+	}
+	t.Fatalf("no QuotaFailure detail in %v", err)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// panicRecoverer turns a panicking handler into a codes.Internal error instead of a crashed server
+type panicRecoverer struct {
+	panics atomic.Int64 // recovered so far
+}
+
+func (r *panicRecoverer) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = r.recovered(ctx, info.FullMethod, p)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func (r *panicRecoverer) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = r.recovered(ss.Context(), info.FullMethod, p)
+		}
+	}()
+	return handler(srv, ss)
+}
+
+// recovered counts and logs a panic; the panic value stays out of the returned error
+func (r *panicRecoverer) recovered(ctx context.Context, fullMethod string, p any) error {
+	total := r.panics.Add(1)
+	slog.ErrorContext(ctx, "Recovered from panic in handler",
+		"grpc.method", fullMethod,
+		"panics_total", total,
+		"panic", p,
+		"stack", string(debug.Stack()),
+	)
+	return status.Error(codes.Internal, "internal server error")
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+
+	"grpc-go-learning/internal/requestid"
+	"grpc-go-learning/internal/rpcstatus"
+	"grpc-go-learning/internal/tracing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// requestIDUnary tags the call with the caller's request ID, or a new one, and echoes it
+// in the response headers and in a RequestInfo detail on errors
+func requestIDUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, id := acceptRequestID(ctx)
+	grpc.SetHeader(ctx, metadata.Pairs(requestid.Header, id))
+
+	resp, err := handler(ctx, req)
+	return resp, withRequestInfo(err, id)
+}
+
+func requestIDStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, id := acceptRequestID(ss.Context())
+	ss.SetHeader(metadata.Pairs(requestid.Header, id))
+
+	err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	return withRequestInfo(err, id)
+}
+
+// acceptRequestID returns a context carrying the caller's request ID; a missing or
+// malformed one is replaced so every log line can still be correlated
+func acceptRequestID(ctx context.Context) (context.Context, string) {
+	id, ok := requestid.FromIncoming(ctx)
+	if !ok {
+		id = requestid.New()
+	}
+	if span := tracing.SpanFromContext(ctx); span != nil {
+		span.SetAttribute("request.id", id)
+	}
+	return requestid.NewContext(ctx, id), id
+}
+
+// withRequestInfo adds the request ID to err as an errdetails.RequestInfo, keeping its code,
+// message and other details
+func withRequestInfo(err error, id string) error {
+	if err == nil {
+		return nil
+	}
+	if requestid.FromError(err) != "" {
+		return err
+	}
+	detailed, detailErr := rpcstatus.Convert(err).WithDetails(&errdetails.RequestInfo{RequestId: id})
+	if detailErr != nil {
+		return err
+	}
+	return detailed.Err()
+}
+
+// contextStream replaces the context of a server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
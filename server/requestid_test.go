@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"grpc-go-learning/internal/requestid"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWithRequestInfoKeepsContextErrorCodes(t *testing.T) {
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{err: context.Canceled, want: codes.Canceled},
+		{err: context.DeadlineExceeded, want: codes.DeadlineExceeded},
+		{err: status.Error(codes.NotFound, "gone"), want: codes.NotFound},
+	}
+
+	for _, tt := range tests {
+		err := withRequestInfo(tt.err, "req_1")
+		if code := status.Code(err); code != tt.want {
+			t.Errorf("withRequestInfo(%v) code = %v, want %v", tt.err, code, tt.want)
+		}
+		if id := requestid.FromError(err); id != "req_1" {
+			t.Errorf("withRequestInfo(%v) request id = %q, want req_1", tt.err, id)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// defaultShutdownTimeout is how long in-flight RPCs may drain at shutdown
+const defaultShutdownTimeout = 30 * time.Second
+
+// defaultDrainDelay is how long new RPCs are still served after health reports NOT_SERVING
+const defaultDrainDelay = 5 * time.Second
+
+// shutdown reports NOT_SERVING, keeps accepting RPCs for drainDelay while load balancers
+// notice, then stops accepting RPCs and waits up to timeout for in-flight ones to finish,
+// cuts off whatever is left and flushes the upload store.
+// metricsServer, if not nil, keeps serving until the RPCs are gone.
+func (s *server) shutdown(grpcServer *grpc.Server, metricsServer *http.Server, drainDelay, timeout time.Duration) {
+	slog.Info("Shutting down, draining RPCs", "drain_delay", drainDelay, "timeout", timeout)
+
+	// Tell health checkers first so load balancers stop routing here, and give them
+	// time to see it before new RPCs are refused
+	s.health.shutdown()
+	time.Sleep(drainDelay)
+
+	// Notification streams never finish on their own, so GracefulStop would wait for the full timeout
+	s.notifications.close()
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-stopped:
+		slog.Info("All RPCs finished")
+	case <-timer.C:
+		slog.Warn("Drain timeout expired, closing remaining RPCs")
+		grpcServer.Stop()
+		<-stopped
+	}
+
+	if metricsServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		metricsServer.Shutdown(ctx)
+	}
+
+	if err := s.blobs.Close(); err != nil {
+		slog.Error("Failed to flush upload store", "error", err)
+	}
+	slog.Info("👋 Server stopped")
+}
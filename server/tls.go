@@ -0,0 +1,163 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultTLSReloadInterval is how often certificate files are checked for changes
+const defaultTLSReloadInterval = time.Minute
+
+const (
+	clientAuthRequire  = "require"
+	clientAuthOptional = "optional"
+)
+
+// clientAuthTypes maps the client_auth setting to how client certificates are checked
+// when a client CA is configured
+var clientAuthTypes = map[string]tls.ClientAuthType{
+	clientAuthRequire:  tls.RequireAndVerifyClientCert,
+	clientAuthOptional: tls.VerifyClientCertIfGiven,
+}
+
+// certReloader holds the server certificate and client CA pool, replacing them when
+// their files change so certificates can be rotated without a restart
+type certReloader struct {
+	certFile, keyFile string
+	clientCAFile      string // empty: client certificates aren't requested
+	clientAuth        tls.ClientAuthType
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	stamp     string // sizes and mtimes of the files at the last load attempt
+}
+
+// newCertReloader loads the configured files; they must be valid at startup
+func newCertReloader(c tlsConfig) (*certReloader, error) {
+	r := &certReloader{
+		certFile:     c.CertFile,
+		keyFile:      c.KeyFile,
+		clientCAFile: c.ClientCAFile,
+		clientAuth:   tls.NoClientCert,
+	}
+	if c.ClientCAFile != "" {
+		r.clientAuth = clientAuthTypes[c.ClientAuth] // checked by validate
+	}
+
+	stamp, err := r.fileStamp()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(stamp); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// fileStamp summarises the files so a change to any of them can be noticed
+func (r *certReloader) fileStamp() (string, error) {
+	var b strings.Builder
+	for _, file := range []string{r.certFile, r.keyFile, r.clientCAFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s:%d:%d;", file, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
+
+// load reads the files and swaps them in only if all of them are valid
+func (r *certReloader) load(stamp string) error {
+	r.mu.Lock()
+	r.stamp = stamp
+	r.mu.Unlock()
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("load certificate: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.clientCAFile != "" {
+		pem, err := os.ReadFile(r.clientCAFile)
+		if err != nil {
+			return fmt.Errorf("read client CA: %w", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in %s", r.clientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.mu.Unlock()
+
+	slog.Info("🔒 TLS certificate loaded",
+		"subject", cert.Leaf.Subject.String(),
+		"not_after", cert.Leaf.NotAfter,
+		"mutual_tls", clientCAs != nil,
+	)
+	return nil
+}
+
+// watch reloads the files whenever they change. A failed reload keeps the previous
+// certificate and is retried on the next change, e.g. once both cert and key are replaced.
+func (r *certReloader) watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		r.reloadIfChanged()
+	}
+}
+
+// reloadIfChanged loads the files if any of them changed since the last load attempt
+func (r *certReloader) reloadIfChanged() {
+	stamp, err := r.fileStamp()
+	if err != nil {
+		slog.Error("Failed to check TLS files", "error", err)
+		return
+	}
+
+	r.mu.RLock()
+	changed := stamp != r.stamp
+	r.mu.RUnlock()
+	if !changed {
+		return
+	}
+
+	if err := r.load(stamp); err != nil {
+		slog.Error("Failed to reload TLS files, keeping the previous certificate", "error", err)
+	}
+}
+
+// tlsConfig returns a server config that picks up the current files on every handshake
+func (r *certReloader) tlsConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.cert},
+				ClientAuth:   r.clientAuth,
+				ClientCAs:    r.clientCAs,
+				NextProtos:   []string{"h2"}, // gRPC clients require ALPN
+			}, nil
+		},
+	}
+}
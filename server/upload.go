@@ -0,0 +1,482 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	userv1 "grpc-go-learning/gen/go/user/v1/user"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// uploadIDHeader is the response header carrying the upload ID, sent as soon as metadata is accepted
+const uploadIDHeader = "x-upload-id"
+
+// uploadState is the lifecycle of an upload session across streams
+type uploadState int
+
+const (
+	// uploadReceiving: a stream is attached and sending chunks
+	uploadReceiving uploadState = iota
+	// uploadParked: the stream ended early; waiting to be resumed or expired
+	uploadParked
+	// uploadCompleted: the blob was committed
+	uploadCompleted
+	// uploadFailed: the data can't be completed and was discarded
+	uploadFailed
+)
+
+// uploadSession is an upload in progress; it outlives a stream that breaks off so it can be resumed
+type uploadSession struct {
+	server   *server
+	uploadID string
+	metadata *userv1.UserMetadata
+	blob     blobWriter
+
+	state    uploadState // guarded by server.pending.mu
+	parkedAt time.Time   // guarded by server.pending.mu
+
+	mu            sync.Mutex // guards the fields below
+	expectedSHA   string
+	bytesReceived int64
+	chunkCount    int32  // also the next expected chunk_number
+	contentType   string // resolved from the first chunk
+}
+
+// UploadUserData implements client-side streaming
+func (s *server) UploadUserData(stream userv1.UserService_UploadUserDataServer) error {
+	// session stays nil until the metadata message arrives
+	var session *uploadSession
+	defer func() {
+		if session != nil {
+			s.pending.release(stream.Context(), session)
+		}
+	}()
+
+	// Receive messages from client
+	for {
+		req, err := stream.Recv()
+
+		// Check if client finished sending
+		if err == io.EOF {
+			if session == nil {
+				return uploadViolation(codes.InvalidArgument, "metadata", "stream closed before metadata was sent")
+			}
+			resp, err := session.finish(stream.Context())
+			if err != nil {
+				return err
+			}
+
+			// Send final response to client
+			return stream.SendAndClose(resp)
+		}
+
+		// Check for errors
+		if err != nil {
+			return recvError(stream.Context(), err)
+		}
+
+		// Process the received message
+		opened := session == nil
+		session, err = s.handleUploadMessage(stream.Context(), session, req)
+		if err == nil && opened {
+			err = stream.SendHeader(metadata.Pairs(uploadIDHeader, session.uploadID))
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// handleUploadMessage applies one upload stream message, returning the session it opened or continued
+func (s *server) handleUploadMessage(ctx context.Context, session *uploadSession, req *userv1.UploadUserDataRequest) (*uploadSession, error) {
+	switch data := req.Data.(type) {
+	case *userv1.UploadUserDataRequest_Metadata:
+		if session != nil {
+			return session, uploadViolation(codes.InvalidArgument, "metadata", "metadata must be sent exactly once")
+		}
+		return s.openUpload(ctx, data.Metadata)
+	case *userv1.UploadUserDataRequest_Chunk:
+		if session == nil {
+			return nil, uploadViolation(codes.InvalidArgument, "chunk", "metadata must be sent before any chunk")
+		}
+		return session, session.handleChunk(ctx, data.Chunk)
+	default:
+		return session, uploadViolation(codes.InvalidArgument, "data", "message must carry metadata or a chunk")
+	}
+}
+
+// openUpload validates the metadata message and starts a new session or resumes a parked one
+func (s *server) openUpload(ctx context.Context, metadata *userv1.UserMetadata) (*uploadSession, error) {
+	expectedSHA, err := s.validateMetadata(ctx, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata.ResumeUploadId != "" {
+		return s.resumeUpload(ctx, metadata, expectedSHA)
+	}
+
+	if err := s.admitUpload(metadata); err != nil {
+		return nil, err
+	}
+
+	uploadID, err := newUploadID()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate upload id: %v", err)
+	}
+
+	// Chunks go straight to the blob store; the blob only becomes visible on Commit
+	blob, err := s.blobs.Create()
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to create blob", "upload_id", uploadID, "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to store upload: %v", err)
+	}
+
+	session := &uploadSession{
+		server:      s,
+		uploadID:    uploadID,
+		metadata:    metadata,
+		blob:        blob,
+		state:       uploadReceiving,
+		expectedSHA: expectedSHA,
+	}
+	s.pending.add(session)
+
+	slog.InfoContext(ctx, "Receiving upload", "upload_id", uploadID, "user_id", metadata.UserId,
+		"filename", metadata.Filename, "total_size", metadata.TotalSize)
+	return session, nil
+}
+
+// validateMetadata checks the fields shared by new and resumed uploads, returning the normalized sha256
+func (s *server) validateMetadata(ctx context.Context, metadata *userv1.UserMetadata) (string, error) {
+	if metadata == nil {
+		return "", uploadViolation(codes.InvalidArgument, "metadata", "metadata must not be empty")
+	}
+	if metadata.UserId == "" {
+		return "", uploadViolation(codes.InvalidArgument, "metadata.user_id", "user_id is required")
+	}
+	if metadata.TotalSize < 0 {
+		return "", uploadViolation(codes.InvalidArgument, "metadata.total_size",
+			fmt.Sprintf("total_size must not be negative, got %d", metadata.TotalSize))
+	}
+	if err := s.checkUploader(ctx, metadata.UserId); err != nil {
+		return "", err
+	}
+
+	if metadata.Sha256 == "" {
+		return "", nil
+	}
+	expectedSHA, err := normalizeSHA256(metadata.Sha256)
+	if err != nil {
+		return "", uploadViolation(codes.InvalidArgument, "metadata.sha256", err.Error())
+	}
+	return expectedSHA, nil
+}
+
+// admitUpload checks the metadata of a new upload against the server's limits.
+// The filename and content_type are rewritten in their sanitized, normalized form.
+func (s *server) admitUpload(metadata *userv1.UserMetadata) error {
+	if metadata.Filename == "" {
+		return uploadViolation(codes.InvalidArgument, "metadata.filename", "filename is required")
+	}
+	filename, err := sanitizeFilename(metadata.Filename)
+	if err != nil {
+		return uploadViolation(codes.InvalidArgument, "metadata.filename", err.Error())
+	}
+	metadata.Filename = filename
+
+	if metadata.ContentType != "" {
+		contentType, err := normalizeContentType(metadata.ContentType)
+		if err != nil {
+			return uploadViolation(codes.InvalidArgument, "metadata.content_type", err.Error())
+		}
+		if !contentTypeAllowed(contentType, s.allowedContentTypes) {
+			return uploadViolation(codes.InvalidArgument, "metadata.content_type",
+				fmt.Sprintf("content type %s is not allowed", contentType))
+		}
+		metadata.ContentType = contentType
+	}
+
+	return s.quotas.admit(metadata.UserId, metadata.TotalSize)
+}
+
+// checkUploader verifies that userID exists, may upload, and is the authenticated caller if there is one
+func (s *server) checkUploader(ctx context.Context, userID string) error {
+	if caller, ok := callerFromContext(ctx); ok && caller != userID {
+		return status.Errorf(codes.PermissionDenied, "caller %s cannot upload on behalf of user %s", caller, userID)
+	}
+
+	s.mu.RLock()
+	user, exists := s.users[userID]
+	s.mu.RUnlock()
+	if !exists {
+		return uploadViolation(codes.NotFound, "metadata.user_id", fmt.Sprintf("user %s not found", userID))
+	}
+
+	switch user.Status {
+	case userv1.UserStatus_USER_STATUS_SUSPENDED:
+		return status.Errorf(codes.PermissionDenied, "user %s is suspended and cannot upload", userID)
+	case userv1.UserStatus_USER_STATUS_INACTIVE:
+		return status.Errorf(codes.FailedPrecondition, "user %s is inactive and cannot upload", userID)
+	}
+	return nil
+}
+
+// resumeUpload reattaches a parked session, checking the metadata still describes the same upload
+func (s *server) resumeUpload(ctx context.Context, metadata *userv1.UserMetadata, expectedSHA string) (*uploadSession, error) {
+	if _, completed := s.uploads.get(metadata.ResumeUploadId); completed {
+		return nil, status.Errorf(codes.FailedPrecondition, "upload %s is already complete", metadata.ResumeUploadId)
+	}
+
+	session, err := s.pending.claim(metadata.ResumeUploadId, metadata.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	// A mismatch leaves the session parked for a corrected attempt
+	violation := func(field, description string) (*uploadSession, error) {
+		s.pending.release(ctx, session)
+		return nil, uploadViolation(codes.InvalidArgument, field, description)
+	}
+	if metadata.TotalSize != session.metadata.TotalSize {
+		return violation("metadata.total_size",
+			fmt.Sprintf("total_size %d does not match the original %d", metadata.TotalSize, session.metadata.TotalSize))
+	}
+	if metadata.Filename != "" {
+		if filename, err := sanitizeFilename(metadata.Filename); err != nil || filename != session.metadata.Filename {
+			return violation("metadata.filename",
+				fmt.Sprintf("filename %q does not match the original %q", metadata.Filename, session.metadata.Filename))
+		}
+	}
+
+	session.mu.Lock()
+	originalSHA := session.expectedSHA
+	if originalSHA == "" {
+		session.expectedSHA = expectedSHA
+	}
+	bytesReceived, chunkCount := session.bytesReceived, session.chunkCount
+	session.mu.Unlock()
+
+	if expectedSHA != "" && originalSHA != "" && expectedSHA != originalSHA {
+		return violation("metadata.sha256", "sha256 does not match the original upload")
+	}
+
+	slog.InfoContext(ctx, "Resuming upload", "upload_id", session.uploadID, "user_id", metadata.UserId,
+		"committed_bytes", bytesReceived, "chunk_count", chunkCount)
+	return session, nil
+}
+
+// handleChunk verifies and stores the next chunk
+func (u *uploadSession) handleChunk(ctx context.Context, chunk *userv1.UserDataChunk) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if chunk == nil {
+		return uploadViolation(codes.InvalidArgument, "chunk", "chunk must not be empty")
+	}
+	if chunk.ChunkNumber != u.chunkCount {
+		return uploadViolation(codes.InvalidArgument, "chunk.chunk_number",
+			fmt.Sprintf("expected chunk %d, got %d", u.chunkCount, chunk.ChunkNumber))
+	}
+
+	chunkSize := int64(len(chunk.Data))
+	if u.bytesReceived+chunkSize > u.metadata.TotalSize {
+		return uploadViolation(codes.OutOfRange, "chunk.data",
+			fmt.Sprintf("chunk %d ends at byte %d, beyond total_size %d", chunk.ChunkNumber, u.bytesReceived+chunkSize, u.metadata.TotalSize))
+	}
+
+	if err := verifyChunkCRC(chunk.Data, chunk.Crc32C); err != nil {
+		slog.WarnContext(ctx, "Chunk is corrupt", "upload_id", u.uploadID, "chunk_number", chunk.ChunkNumber, "error", err)
+		return status.Errorf(codes.DataLoss, "chunk %d is corrupt: %v", chunk.ChunkNumber, err)
+	}
+
+	if u.bytesReceived == 0 && chunkSize > 0 {
+		contentType, err := u.server.checkContent(u.metadata.ContentType, u.metadata.Filename, chunk.Data)
+		if err != nil {
+			return err
+		}
+		u.contentType = contentType
+	}
+
+	// Concurrent uploads can each pass the up-front check, so the quota is charged as data arrives
+	if err := u.server.quotas.charge(u.metadata.UserId, chunkSize); err != nil {
+		return err
+	}
+
+	if _, err := u.blob.Write(chunk.Data); err != nil {
+		slog.ErrorContext(ctx, "Failed to write chunk", "upload_id", u.uploadID, "chunk_number", chunk.ChunkNumber, "error", err)
+		// The blob may hold part of the chunk, so the upload can't be resumed
+		u.server.quotas.release(u.metadata.UserId, chunkSize)
+		u.fail()
+		return status.Errorf(codes.Internal, "failed to store chunk: %v", err)
+	}
+	u.bytesReceived += chunkSize
+	u.chunkCount++
+	u.server.metrics.uploadBytesReceived.Add(chunkSize)
+
+	slog.DebugContext(ctx, "Received chunk", "upload_id", u.uploadID, "chunk_number", chunk.ChunkNumber,
+		"bytes", chunkSize, "committed_bytes", u.bytesReceived, "total_size", u.metadata.TotalSize)
+	return nil
+}
+
+// finish validates the completed upload and commits the blob
+func (u *uploadSession) finish(ctx context.Context) (*userv1.UploadUserDataResponse, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	slog.InfoContext(ctx, "Client finished sending", "upload_id", u.uploadID, "bytes", u.bytesReceived, "chunks", u.chunkCount)
+
+	// Too few bytes leaves the session parked so the rest can be sent on resume
+	if u.bytesReceived != u.metadata.TotalSize {
+		return nil, uploadViolation(codes.OutOfRange, "metadata.total_size",
+			fmt.Sprintf("received %d bytes, expected total_size %d", u.bytesReceived, u.metadata.TotalSize))
+	}
+
+	computedSHA := u.blob.Digest()
+	if u.expectedSHA != "" && computedSHA != u.expectedSHA {
+		slog.WarnContext(ctx, "Upload is corrupt", "upload_id", u.uploadID, "expected_sha256", u.expectedSHA, "computed_sha256", computedSHA)
+		u.fail()
+		return nil, status.Errorf(codes.DataLoss, "sha256 mismatch: expected %s, computed %s", u.expectedSHA, computedSHA)
+	}
+
+	if err := u.blob.Commit(); err != nil {
+		slog.ErrorContext(ctx, "Failed to commit upload", "upload_id", u.uploadID, "error", err)
+		u.fail()
+		return nil, status.Errorf(codes.Internal, "failed to store upload: %v", err)
+	}
+	err := u.server.recordUpload(ctx, &uploadRecord{
+		uploadID:    u.uploadID,
+		userID:      u.metadata.UserId,
+		filename:    u.metadata.Filename,
+		contentType: resolvedContentType(u.metadata.ContentType, u.contentType),
+		size:        u.bytesReceived,
+		chunkCount:  u.chunkCount,
+		sha256:      computedSHA,
+		createdAt:   time.Now(),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to record upload", "upload_id", u.uploadID, "error", err)
+		u.fail()
+		return nil, status.Errorf(codes.Internal, "failed to store upload: %v", err)
+	}
+	u.server.quotas.complete(u.metadata.UserId, u.bytesReceived)
+	u.server.pending.mark(u, uploadCompleted)
+
+	return &userv1.UploadUserDataResponse{
+		UploadId:      u.uploadID,
+		BytesReceived: u.bytesReceived,
+		Success:       true,
+		Sha256:        computedSHA,
+	}, nil
+}
+
+// fail discards the upload and returns its bytes to the user's quota; u.mu must be held
+func (u *uploadSession) fail() {
+	u.blob.Abort()
+	u.server.quotas.release(u.metadata.UserId, u.bytesReceived)
+	u.server.pending.mark(u, uploadFailed)
+}
+
+// resolvedContentType picks the media type to record for an upload
+func resolvedContentType(declared, detected string) string {
+	if detected != "" {
+		return detected
+	}
+	if declared != "" {
+		return declared
+	}
+	return "application/octet-stream"
+}
+
+// progress returns the committed byte and chunk counts
+func (u *uploadSession) progress() (int64, int32) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.bytesReceived, u.chunkCount
+}
+
+// GetUploadStatus reports how much of an upload has been committed
+func (s *server) GetUploadStatus(ctx context.Context, req *userv1.GetUploadStatusRequest) (*userv1.GetUploadStatusResponse, error) {
+	if req.UploadId == "" {
+		return nil, status.Error(codes.InvalidArgument, "upload_id is required")
+	}
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if record, exists := s.uploads.get(req.UploadId); exists {
+		if record.userID != req.UserId {
+			return nil, status.Errorf(codes.PermissionDenied, "upload %s belongs to another user", req.UploadId)
+		}
+		return &userv1.GetUploadStatusResponse{
+			UploadId:       record.uploadID,
+			State:          userv1.UploadState_UPLOAD_STATE_COMPLETED,
+			CommittedBytes: record.size,
+			ChunkCount:     record.chunkCount,
+			TotalSize:      record.size,
+		}, nil
+	}
+
+	session, expiresAt, exists := s.pending.get(req.UploadId)
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "upload %s not found", req.UploadId)
+	}
+	if session.metadata.UserId != req.UserId {
+		return nil, status.Errorf(codes.PermissionDenied, "upload %s belongs to another user", req.UploadId)
+	}
+
+	committedBytes, chunkCount := session.progress()
+	resp := &userv1.GetUploadStatusResponse{
+		UploadId:       session.uploadID,
+		State:          userv1.UploadState_UPLOAD_STATE_IN_PROGRESS,
+		CommittedBytes: committedBytes,
+		ChunkCount:     chunkCount,
+		TotalSize:      session.metadata.TotalSize,
+	}
+	if !expiresAt.IsZero() {
+		resp.ExpiresAt = expiresAt.Unix()
+	}
+	return resp, nil
+}
+
+// uploadViolation builds a status error naming the offending field in a BadRequest detail
+func uploadViolation(code codes.Code, field, description string) error {
+	st := status.New(code, fmt.Sprintf("%s: %s", field, description))
+	detailed, err := st.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: field, Description: description},
+		},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// recvError reports a failed Recv. A client that went away or ran out of time, which is how
+// an upload is left to be resumed, gets Canceled or DeadlineExceeded; anything else is Internal.
+func recvError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return status.Errorf(codes.Internal, "failed to receive data: %v", err)
+}
+
+// newUploadID returns a random upload ID
+func newUploadID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "upload_" + hex.EncodeToString(b), nil
+}
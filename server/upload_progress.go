@@ -0,0 +1,119 @@
+package main
+
+import (
+	"io"
+	"time"
+
+	userv1 "grpc-go-learning/gen/go/user/v1/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// uploadAckInterval is how often UploadUserDataWithProgress acknowledges new progress
+const uploadAckInterval = 250 * time.Millisecond
+
+// uploadMessage is one result of receiving from an upload stream
+type uploadMessage struct {
+	req *userv1.UploadUserDataRequest
+	err error
+}
+
+// UploadUserDataWithProgress implements bidirectional streaming: the client sends the same
+// messages as UploadUserData and the server acknowledges the committed offset as it advances
+func (s *server) UploadUserDataWithProgress(stream userv1.UserService_UploadUserDataWithProgressServer) error {
+	ctx := stream.Context()
+
+	// session stays nil until the metadata message arrives
+	var session *uploadSession
+	defer func() {
+		if session != nil {
+			s.pending.release(ctx, session)
+		}
+	}()
+
+	// Receive on a separate goroutine so acks can be sent while waiting for the next message;
+	// stream.Send is only called from this one
+	messages := make(chan uploadMessage)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			select {
+			case messages <- uploadMessage{req: req, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(uploadAckInterval)
+	defer ticker.Stop()
+
+	// ackedChunks is the chunk count last sent to the client
+	var ackedChunks int32
+	ack := func(result *userv1.UploadUserDataResponse) error {
+		committedBytes, chunkCount := session.progress()
+		ackedChunks = chunkCount
+		return stream.Send(&userv1.UploadProgress{
+			UploadId:       session.uploadID,
+			CommittedBytes: committedBytes,
+			ChunkCount:     chunkCount,
+			TotalSize:      session.metadata.TotalSize,
+			Result:         result,
+		})
+	}
+
+	for {
+		var msg uploadMessage
+		select {
+		case msg = <-messages:
+		case <-ticker.C:
+			if session == nil {
+				continue
+			}
+			if _, chunkCount := session.progress(); chunkCount == ackedChunks {
+				continue
+			}
+			if err := ack(nil); err != nil {
+				return err
+			}
+			continue
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+
+		// Check if client finished sending
+		if msg.err == io.EOF {
+			if session == nil {
+				return uploadViolation(codes.InvalidArgument, "metadata", "stream closed before metadata was sent")
+			}
+			resp, err := session.finish(ctx)
+			if err != nil {
+				return err
+			}
+
+			// The last ack carries the result
+			return ack(resp)
+		}
+
+		// Check for errors
+		if msg.err != nil {
+			return recvError(ctx, msg.err)
+		}
+
+		// Process the received message
+		opened := session == nil
+		var err error
+		session, err = s.handleUploadMessage(ctx, session, msg.req)
+		if err == nil && opened {
+			// The first ack names the upload and, on resume, where to continue from
+			err = ack(nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	userv1 "grpc-go-learning/gen/go/user/v1/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// uploadRecord describes a committed upload
+type uploadRecord struct {
+	uploadID    string
+	userID      string
+	filename    string
+	contentType string
+	size        int64
+	chunkCount  int32
+	sha256      string
+	createdAt   time.Time
+}
+
+// uploadRecordFile is the stored form of an uploadRecord
+type uploadRecordFile struct {
+	UploadID    string    `json:"upload_id"`
+	UserID      string    `json:"user_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	ChunkCount  int32     `json:"chunk_count"`
+	SHA256      string    `json:"sha256"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// uploadIndex keeps the metadata of committed uploads in memory and, when it
+// has a directory, in one file per upload so the index survives a restart
+type uploadIndex struct {
+	dir string // empty keeps the index in memory only
+
+	mu      sync.RWMutex
+	uploads map[string]*uploadRecord // upload_id -> record
+}
+
+func newUploadIndex() *uploadIndex {
+	return &uploadIndex{
+		uploads: make(map[string]*uploadRecord),
+	}
+}
+
+// openUploadIndex loads the records stored in dir. A record that can't be read is
+// an error rather than skipped, since its data would then look unreferenced.
+func openUploadIndex(dir string) (*uploadIndex, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create upload index directory: %w", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read upload index: %w", err)
+	}
+
+	u := newUploadIndex()
+	u.dir = dir
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasSuffix(name, ".tmp") {
+			// Left by a write that never completed
+			os.Remove(filepath.Join(dir, name))
+			continue
+		}
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("read upload record %s: %w", name, err)
+		}
+		var file uploadRecordFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("parse upload record %s: %w", name, err)
+		}
+		if file.UploadID+".json" != name {
+			return nil, fmt.Errorf("upload record %s holds upload %q", name, file.UploadID)
+		}
+		u.uploads[file.UploadID] = &uploadRecord{
+			uploadID:    file.UploadID,
+			userID:      file.UserID,
+			filename:    file.Filename,
+			contentType: file.ContentType,
+			size:        file.Size,
+			chunkCount:  file.ChunkCount,
+			sha256:      file.SHA256,
+			createdAt:   file.CreatedAt,
+		}
+	}
+	return u, nil
+}
+
+// add stores record; once it returns without error the upload survives a restart
+func (u *uploadIndex) add(record *uploadRecord) error {
+	if u.dir != "" {
+		if err := u.write(record); err != nil {
+			return err
+		}
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.uploads[record.uploadID] = record
+	return nil
+}
+
+// write stores record in its file, replacing it atomically
+func (u *uploadIndex) write(record *uploadRecord) error {
+	data, err := json.Marshal(uploadRecordFile{
+		UploadID:    record.uploadID,
+		UserID:      record.userID,
+		Filename:    record.filename,
+		ContentType: record.contentType,
+		Size:        record.size,
+		ChunkCount:  record.chunkCount,
+		SHA256:      record.sha256,
+		CreatedAt:   record.createdAt,
+	})
+	if err != nil {
+		return fmt.Errorf("encode upload record: %w", err)
+	}
+
+	path := u.path(record.uploadID)
+	f, err := os.Create(path + ".tmp")
+	if err != nil {
+		return fmt.Errorf("write upload record: %w", err)
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(path+".tmp", path)
+	}
+	if err != nil {
+		os.Remove(path + ".tmp")
+		return fmt.Errorf("write upload record: %w", err)
+	}
+	return syncDir(u.dir)
+}
+
+func (u *uploadIndex) path(uploadID string) string {
+	return filepath.Join(u.dir, uploadID+".json")
+}
+
+func (u *uploadIndex) get(uploadID string) (*uploadRecord, bool) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	record, ok := u.uploads[uploadID]
+	return record, ok
+}
+
+// digests returns the blob digest of every upload, one entry per upload
+func (u *uploadIndex) digests() []string {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	digests := make([]string, 0, len(u.uploads))
+	for _, record := range u.uploads {
+		digests = append(digests, record.sha256)
+	}
+	return digests
+}
+
+// all returns every record, in no particular order
+func (u *uploadIndex) all() []*uploadRecord {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	records := make([]*uploadRecord, 0, len(u.uploads))
+	for _, record := range u.uploads {
+		records = append(records, record)
+	}
+	return records
+}
+
+// remove deletes the record for uploadID, returning it. The record is kept
+// when its file can't be deleted, so it doesn't come back after a restart.
+func (u *uploadIndex) remove(uploadID string) (*uploadRecord, bool, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	record, ok := u.uploads[uploadID]
+	if !ok {
+		return nil, false, nil
+	}
+	if u.dir != "" {
+		if err := os.Remove(u.path(uploadID)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, false, fmt.Errorf("delete upload record: %w", err)
+		}
+	}
+	delete(u.uploads, uploadID)
+	return record, true, nil
+}
+
+// listAfter returns up to limit of userID's uploads ordered by creation time,
+// starting after the upload at cursor (the zero cursor starts at the beginning)
+func (u *uploadIndex) listAfter(userID string, cursor uploadCursor, limit int) ([]*uploadRecord, bool) {
+	u.mu.RLock()
+	var records []*uploadRecord
+	for _, record := range u.uploads {
+		if record.userID == userID && cursor.before(record) {
+			records = append(records, record)
+		}
+	}
+	u.mu.RUnlock()
+
+	sort.Slice(records, func(i, j int) bool {
+		return cursorOf(records[i]).before(records[j])
+	})
+
+	if len(records) > limit {
+		return records[:limit], true
+	}
+	return records, false
+}
+
+// uploadCursor is a position in the creation-time ordering of uploads
+type uploadCursor struct {
+	createdAt int64 // Unix nanoseconds
+	uploadID  string
+}
+
+func cursorOf(record *uploadRecord) uploadCursor {
+	return uploadCursor{createdAt: record.createdAt.UnixNano(), uploadID: record.uploadID}
+}
+
+// before reports whether record sorts after the cursor position
+func (c uploadCursor) before(record *uploadRecord) bool {
+	createdAt := record.createdAt.UnixNano()
+	if createdAt != c.createdAt {
+		return createdAt > c.createdAt
+	}
+	return record.uploadID > c.uploadID
+}
+
+// encode returns an opaque page token for the cursor
+func (c uploadCursor) encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d/%s", c.createdAt, c.uploadID)))
+}
+
+// decodeUploadCursor parses a page token produced by encode
+func decodeUploadCursor(token string) (uploadCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return uploadCursor{}, err
+	}
+	createdAt, uploadID, ok := strings.Cut(string(raw), "/")
+	if !ok {
+		return uploadCursor{}, fmt.Errorf("malformed cursor")
+	}
+	nanos, err := strconv.ParseInt(createdAt, 10, 64)
+	if err != nil {
+		return uploadCursor{}, err
+	}
+	return uploadCursor{createdAt: nanos, uploadID: uploadID}, nil
+}
+
+const (
+	// defaultListUploadsPageSize is used when the request doesn't set page_size
+	defaultListUploadsPageSize = 50
+	// maxListUploadsPageSize caps page_size
+	maxListUploadsPageSize = 1000
+)
+
+// ListUploads returns a page of a user's completed uploads
+func (s *server) ListUploads(ctx context.Context, req *userv1.ListUploadsRequest) (*userv1.ListUploadsResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.PageSize < 0 || req.PageSize > maxListUploadsPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be between 0 and %d", maxListUploadsPageSize)
+	}
+
+	pageSize := defaultListUploadsPageSize
+	if req.PageSize > 0 {
+		pageSize = int(req.PageSize)
+	}
+
+	// The zero cursor sorts before every upload
+	cursor := uploadCursor{createdAt: math.MinInt64}
+	if req.PageToken != "" {
+		var err error
+		cursor, err = decodeUploadCursor(req.PageToken)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page_token: %v", err)
+		}
+	}
+
+	records, more := s.uploads.listAfter(req.UserId, cursor, pageSize)
+
+	resp := &userv1.ListUploadsResponse{}
+	for _, record := range records {
+		resp.Uploads = append(resp.Uploads, &userv1.UploadInfo{
+			UploadId:    record.uploadID,
+			UserId:      record.userID,
+			Filename:    record.filename,
+			ContentType: record.contentType,
+			Size:        record.size,
+			Sha256:      record.sha256,
+			CreatedAt:   record.createdAt.Unix(),
+		})
+	}
+	if more {
+		resp.NextPageToken = cursorOf(records[len(records)-1]).encode()
+	}
+	return resp, nil
+}
+
+// DeleteUpload removes a completed upload and returns its bytes to the user's quota
+func (s *server) DeleteUpload(ctx context.Context, req *userv1.DeleteUploadRequest) (*userv1.DeleteUploadResponse, error) {
+	if req.UploadId == "" {
+		return nil, status.Error(codes.InvalidArgument, "upload_id is required")
+	}
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	record, exists := s.uploads.get(req.UploadId)
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "upload %s not found", req.UploadId)
+	}
+	if record.userID != req.UserId {
+		return nil, status.Errorf(codes.PermissionDenied, "upload %s belongs to another user", req.UploadId)
+	}
+
+	// A concurrent delete may have won the race
+	_, removed, err := s.uploads.remove(req.UploadId)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to delete upload record", "upload_id", req.UploadId, "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to delete upload: %v", err)
+	}
+	if !removed {
+		return nil, status.Errorf(codes.NotFound, "upload %s not found", req.UploadId)
+	}
+	s.quotas.free(record.userID, record.size)
+	s.processing.forget(req.UploadId)
+
+	// The data is shared with identical uploads and only deleted with the last reference
+	if err := s.blobs.Release(record.sha256); err != nil {
+		slog.ErrorContext(ctx, "Failed to release blob", "sha256", record.sha256, "upload_id", req.UploadId, "error", err)
+	}
+
+	slog.InfoContext(ctx, "Upload deleted", "upload_id", req.UploadId)
+	return &userv1.DeleteUploadResponse{
+		UploadId:   req.UploadId,
+		BytesFreed: record.size,
+	}, nil
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	userv1 "grpc-go-learning/gen/go/user/v1/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUploadIndexSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	index, err := openUploadIndex(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kept := &uploadRecord{
+		uploadID:    "upload_kept",
+		userID:      testUserID,
+		filename:    "report.pdf",
+		contentType: "application/pdf",
+		size:        42,
+		chunkCount:  3,
+		sha256:      "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		createdAt:   time.Unix(1700000000, 123456789),
+	}
+	deleted := &uploadRecord{uploadID: "upload_deleted", userID: testUserID, createdAt: time.Now()}
+	for _, record := range []*uploadRecord{kept, deleted} {
+		if err := index.add(record); err != nil {
+			t.Fatalf("add %s: %v", record.uploadID, err)
+		}
+	}
+	if _, removed, err := index.remove(deleted.uploadID); err != nil || !removed {
+		t.Fatalf("remove = %v, %v", removed, err)
+	}
+
+	// A write interrupted by a crash leaves only a temporary file
+	if err := os.WriteFile(filepath.Join(dir, "upload_partial.json.tmp"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := openUploadIndex(dir)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if got := reopened.all(); len(got) != 1 {
+		t.Fatalf("reopened index holds %d uploads, want 1", len(got))
+	}
+	got, ok := reopened.get(kept.uploadID)
+	if !ok {
+		t.Fatal("upload lost across a restart")
+	}
+	if !got.createdAt.Equal(kept.createdAt) {
+		t.Fatalf("createdAt = %v, want %v", got.createdAt, kept.createdAt)
+	}
+	got.createdAt = kept.createdAt
+	if *got != *kept {
+		t.Fatalf("reopened record = %+v, want %+v", *got, *kept)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "upload_partial.json.tmp")); !os.IsNotExist(err) {
+		t.Fatalf("temporary record file not cleaned up: %v", err)
+	}
+}
+
+func TestOpenUploadIndexRejectsUnreadableRecord(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "upload_bad.json"), []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := openUploadIndex(dir); err == nil {
+		t.Fatal("openUploadIndex accepted a corrupt record, its data would be reclaimed")
+	}
+}
+
+func TestUploadCursorRoundTrip(t *testing.T) {
+	for _, cursor := range []uploadCursor{
+		{createdAt: 1700000000123456789, uploadID: "upload_0123abcd"},
+		{createdAt: math.MinInt64},
+		{createdAt: -5, uploadID: "upload/with/slashes"},
+	} {
+		got, err := decodeUploadCursor(cursor.encode())
+		if err != nil {
+			t.Fatalf("decode(encode(%+v)): %v", cursor, err)
+		}
+		if got != cursor {
+			t.Fatalf("decode(encode(%+v)) = %+v", cursor, got)
+		}
+	}
+}
+
+func TestListUploadsRejectsMalformedPageToken(t *testing.T) {
+	s := newTestServer(t)
+	for name, token := range map[string]string{
+		"not base64":        "%%%",
+		"padded base64":     base64.URLEncoding.EncodeToString([]byte("1/upload_1")),
+		"no separator":      base64.RawURLEncoding.EncodeToString([]byte("1700000000")),
+		"non-numeric time":  base64.RawURLEncoding.EncodeToString([]byte("yesterday/upload_1")),
+		"time out of range": base64.RawURLEncoding.EncodeToString([]byte("99999999999999999999/upload_1")),
+	} {
+		_, err := s.ListUploads(context.Background(), &userv1.ListUploadsRequest{UserId: testUserID, PageToken: token})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: got %v, want InvalidArgument", name, err)
+		}
+	}
+}
+
+func TestListUploadsPagesAcrossDeletes(t *testing.T) {
+	s := newTestServer(t)
+	base := time.Unix(1700000000, 0)
+	// Six uploads, two of them sharing a timestamp so the ID breaks the tie
+	for i, offset := range []int{0, 1, 1, 2, 3, 4} {
+		s.uploads.add(&uploadRecord{
+			uploadID:  fmt.Sprintf("upload_%d", i),
+			userID:    testUserID,
+			createdAt: base.Add(time.Duration(offset) * time.Second),
+		})
+	}
+	s.uploads.add(&uploadRecord{uploadID: "upload_other", userID: "user_2", createdAt: base})
+
+	tests := []struct {
+		name    string
+		deletes []string // removed before the next page is fetched
+		want    []string
+	}{
+		{name: "first page", want: []string{"upload_0", "upload_1"}},
+		{name: "cursor's own upload deleted", deletes: []string{"upload_1"}, want: []string{"upload_2", "upload_3"}},
+		{name: "upcoming upload deleted", deletes: []string{"upload_4"}, want: []string{"upload_5"}},
+	}
+
+	token := ""
+	for i, tt := range tests {
+		for _, uploadID := range tt.deletes {
+			if _, removed, err := s.uploads.remove(uploadID); err != nil || !removed {
+				t.Fatalf("remove %s = %v, %v", uploadID, removed, err)
+			}
+		}
+
+		resp, err := s.ListUploads(context.Background(), &userv1.ListUploadsRequest{UserId: testUserID, PageSize: 2, PageToken: token})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var got []string
+		for _, upload := range resp.Uploads {
+			got = append(got, upload.UploadId)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Fatalf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+
+		last := i == len(tests)-1
+		if (resp.NextPageToken == "") != last {
+			t.Fatalf("%s: next_page_token %q, want one only before the last page", tt.name, resp.NextPageToken)
+		}
+		token = resp.NextPageToken
+	}
+}